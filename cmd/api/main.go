@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/henrique/address-validator/config"
@@ -23,7 +25,7 @@ import (
 // @description     The API accepts addresses entered naturally by the user and returns normalized components.
 
 // @host      localhost:3000
-// @BasePath  /api/v1
+// @BasePath  /api
 
 // @securityDefinitions.apikey BearerAuth
 // @in header
@@ -56,30 +58,109 @@ func main() {
 	}
 	defer cache.Close()
 
-	geocodingService := services.NewGeocodingService(
-		cfg.GeocodingAAPIKey,
-		cfg.GeocodingABaseURL,
-		cfg.GeocodingBAPIKey,
-		cfg.GeocodingBBaseURL,
-		cache,
-	)
-	validatorService := services.NewValidatorService(geocodingService, cache)
+	registry := services.DefaultProviderRegistry()
+	providers := make([]services.Provider, 0, len(cfg.GeocodingProviders))
+	for _, providerCfg := range cfg.GeocodingProviders {
+		if providerCfg.APIKey == "" {
+			log.Printf("Geocoding provider %s has no API key configured, skipping", providerCfg.Name)
+			continue
+		}
+
+		provider, err := registry.Create(providerCfg.Name, services.ProviderOptions{
+			APIKey:     providerCfg.APIKey,
+			BaseURL:    providerCfg.BaseURL,
+			MaxResults: cfg.MaxCandidates,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize geocoding provider: %v", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	gazetteer := services.DefaultGazetteer()
+	if cfg.GazetteerPath != "" {
+		gazetteer, err = services.LoadGazetteerFile(cfg.GazetteerPath)
+		if err != nil {
+			log.Fatalf("Failed to load gazetteer: %v", err)
+		}
+	}
+	log.Printf("Gazetteer loaded with %d places", gazetteer.Len())
+	if !gazetteer.Complete() {
+		log.Printf("Warning: the gazetteer is a partial seed list; city/state and city/ZIP warnings are reported with low confidence. Set GAZETTEER_PATH to the GeoNames US.txt dump (make gazetteer) for full coverage")
+	}
+
+	if cfg.DictionaryPath != "" {
+		dictionaryLoader := services.NewDictionaryLoader(cfg.DictionaryPath)
+		dictionaries, err := dictionaryLoader.Load()
+		if err != nil {
+			log.Fatalf("Failed to load dictionaries: %v", err)
+		}
+		log.Printf("Dictionaries loaded from %s, version %s", cfg.DictionaryPath, dictionaries.Version)
+
+		hangup := make(chan os.Signal, 1)
+		signal.Notify(hangup, syscall.SIGHUP)
+		go dictionaryLoader.Watch(cfg.DictionaryReloadInterval, hangup, nil)
+	}
+
+	var tenants middleware.TenantLookup
+	if cfg.TenantsPath != "" {
+		tenantRegistry, err := services.LoadTenantsFile(cfg.TenantsPath)
+		if err != nil {
+			log.Fatalf("Failed to load tenants: %v", err)
+		}
+		if _, found := tenantRegistry.Lookup(cfg.APIToken); found {
+			log.Fatal("A tenant cannot use API_TOKEN as its token")
+		}
+		log.Printf("Tenants loaded: %d", tenantRegistry.Len())
+		tenants = tenantRegistry
+	}
+
+	geocodingService := services.NewGeocodingService(providers, cache)
+	validatorService := services.NewValidatorService(geocodingService, cache, gazetteer)
+
+	jobService := services.NewJobService(validatorService, cache, cfg.BatchWorkers)
+	autocompleteService := services.NewAutocompleteService(geocodingService, cache, cfg.AutocompleteTimeout)
 
-	addressHandler := handlers.NewAddressHandler(validatorService)
+	addressHandler := handlers.NewAddressHandler(validatorService, cfg.BatchMaxSize, cfg.BatchWorkers)
+	jobHandler := handlers.NewJobHandler(jobService, cfg.JobMaxUploadSize)
+	autocompleteHandler := handlers.NewAutocompleteHandler(autocompleteService, cfg.AutocompleteLimit)
 
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger())
 
-	router.GET("/health", healthCheck)
+	router.GET("/health", healthCheck(gazetteer))
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	v1 := router.Group("/api/v1")
-	v1.Use(middleware.BearerAuth(cfg.APIToken))
-	v1.Use(middleware.ValidateHeaders())
+	v1.Use(middleware.BearerAuth(cfg.APIToken, tenants))
+
+	api := v1.Group("")
+	api.Use(middleware.ValidateHeaders())
+	{
+		api.POST("/validate-address", addressHandler.ValidateAddress)
+		api.POST("/validate-address/structured", addressHandler.ValidateStructuredAddress)
+		api.POST("/validate-addresses", addressHandler.ValidateAddresses)
+		api.GET("/autocomplete", autocompleteHandler.Autocomplete)
+		api.POST("/reverse-geocode", addressHandler.ReverseGeocode)
+	}
+
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.BearerAuth(cfg.APIToken, tenants))
+	v2.Use(middleware.ValidateHeaders())
+	{
+		v2.POST("/validate-address", addressHandler.ValidateAddressV2)
+		v2.POST("/validate-address/structured", addressHandler.ValidateStructuredAddressV2)
+		v2.POST("/validate-addresses", addressHandler.ValidateAddressesV2)
+	}
+
+	jobs := v1.Group("/jobs")
+	jobs.Use(middleware.ValidateHeadersFor([]string{"multipart/form-data"}, []string{"application/json", "text/csv"}))
 	{
-		v1.POST("/validate-address", addressHandler.ValidateAddress)
+		jobs.POST("", jobHandler.CreateJob)
+		jobs.GET("/:id", jobHandler.GetJob)
+		jobs.GET("/:id/result", jobHandler.GetJobResult)
 	}
 
 	port := os.Getenv("PORT")
@@ -96,14 +177,22 @@ func main() {
 
 // healthCheck godoc
 // @Summary      Health check
-// @Description  Check if the service is working
+// @Description  Check if the service is working. `gazetteer` is "partial" when only the bundled seed list of places is loaded
 // @Tags         health
 // @Produce      json
 // @Success      200  {object}  map[string]string
 // @Router       /health [get]
-func healthCheck(c *gin.Context) {
-	c.JSON(200, gin.H{
-		"status":  "healthy",
-		"service": "address-validator",
-	})
+func healthCheck(gazetteer *services.Gazetteer) gin.HandlerFunc {
+	coverage := "complete"
+	if !gazetteer.Complete() {
+		coverage = "partial"
+	}
+
+	return func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":    "healthy",
+			"service":   "address-validator",
+			"gazetteer": coverage,
+		})
+	}
 }
@@ -3,41 +3,99 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Port              string
-	GeocodingAAPIKey  string
-	GeocodingABaseURL string
-	GeocodingBAPIKey  string
-	GeocodingBBaseURL string
-	CacheTTL          time.Duration
-	Environment       string
-	RedisHost         string
-	RedisPort         int
-	RedisPassword     string
-	RedisDB           int
-	APIToken          string
+	Port                string
+	GeocodingProviders  []ProviderConfig
+	MaxCandidates       int
+	CacheTTL            time.Duration
+	Environment         string
+	RedisHost           string
+	RedisPort           int
+	RedisPassword       string
+	RedisDB             int
+	APIToken            string
+	BatchMaxSize        int
+	BatchWorkers        int
+	JobMaxUploadSize    int64
+	AutocompleteLimit   int
+	AutocompleteTimeout time.Duration
+	GazetteerPath       string
+
+	DictionaryPath           string
+	DictionaryReloadInterval time.Duration
+	TenantsPath              string
+}
+
+type ProviderConfig struct {
+	Name    string
+	APIKey  string
+	BaseURL string
+}
+
+// legacyProviderEnv keeps the GEOCODING_A_* (Geoapify) and GEOCODING_B_* (Smarty)
+// variables working for the providers they always configured, wherever those
+// providers are in the chain.
+var legacyProviderEnv = map[string]string{
+	"geoapify": "GEOCODING_A",
+	"smarty":   "GEOCODING_B",
 }
 
 func Load() *Config {
 	return &Config{
-		Port:              getEnv("PORT", "3000"),
-		GeocodingAAPIKey:  getEnv("GEOCODING_A_API_KEY", ""),
-		GeocodingABaseURL: getEnv("GEOCODING_A_BASE_URL", "https://api.geoapify.com/v1/geocode/search"),
-		GeocodingBAPIKey:  getEnv("GEOCODING_B_API_KEY", ""),
-		GeocodingBBaseURL: getEnv("GEOCODING_B_BASE_URL", " https://us-autocomplete-pro.api.smarty.com/lookup"),
-		CacheTTL:          parseDuration(getEnv("CACHE_TTL", "24h")),
-		Environment:       getEnv("ENVIRONMENT", "development"),
-		RedisHost:         getEnv("REDIS_HOST", "localhost"),
-		RedisPort:         parseInt(getEnv("REDIS_PORT", "6379")),
-		RedisPassword:     getEnv("REDIS_PASSWORD", ""),
-		RedisDB:           parseInt(getEnv("REDIS_DB", "0")),
-		APIToken:          getEnv("API_TOKEN", ""),
+		Port:                getEnv("PORT", "3000"),
+		GeocodingProviders:  loadProviders(getEnv("GEOCODING_PROVIDERS", "geoapify,smarty")),
+		MaxCandidates:       parseInt(getEnv("MAX_CANDIDATES", "5")),
+		CacheTTL:            parseDuration(getEnv("CACHE_TTL", "24h")),
+		Environment:         getEnv("ENVIRONMENT", "development"),
+		RedisHost:           getEnv("REDIS_HOST", "localhost"),
+		RedisPort:           parseInt(getEnv("REDIS_PORT", "6379")),
+		RedisPassword:       getEnv("REDIS_PASSWORD", ""),
+		RedisDB:             parseInt(getEnv("REDIS_DB", "0")),
+		APIToken:            getEnv("API_TOKEN", ""),
+		BatchMaxSize:        parseInt(getEnv("BATCH_MAX_SIZE", "1000")),
+		BatchWorkers:        parseInt(getEnv("BATCH_WORKERS", "8")),
+		JobMaxUploadSize:    int64(parseInt(getEnv("JOB_MAX_UPLOAD_MB", "50"))) << 20,
+		AutocompleteLimit:   parseInt(getEnv("AUTOCOMPLETE_MAX_LIMIT", "10")),
+		AutocompleteTimeout: parseDurationDefault(getEnv("AUTOCOMPLETE_TIMEOUT", "2s"), 2*time.Second),
+		GazetteerPath:       getEnv("GAZETTEER_PATH", ""),
+
+		DictionaryPath:           getEnv("DICTIONARY_PATH", ""),
+		DictionaryReloadInterval: parseDurationDefault(getEnv("DICTIONARY_RELOAD_INTERVAL", "30s"), 30*time.Second),
+		TenantsPath:              getEnv("TENANTS_PATH", ""),
 	}
 }
 
+func loadProviders(chain string) []ProviderConfig {
+	providers := []ProviderConfig{}
+
+	for _, name := range strings.Split(chain, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "GEOCODING_" + strings.ToUpper(name)
+		apiKey := getEnv(prefix+"_API_KEY", "")
+		baseURL := getEnv(prefix+"_BASE_URL", "")
+		if legacy, exists := legacyProviderEnv[name]; exists {
+			apiKey = getEnv(prefix+"_API_KEY", getEnv(legacy+"_API_KEY", ""))
+			baseURL = getEnv(prefix+"_BASE_URL", getEnv(legacy+"_BASE_URL", ""))
+		}
+
+		providers = append(providers, ProviderConfig{
+			Name:    name,
+			APIKey:  apiKey,
+			BaseURL: strings.TrimSpace(baseURL),
+		})
+	}
+
+	return providers
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -46,9 +104,13 @@ func getEnv(key, defaultValue string) string {
 }
 
 func parseDuration(s string) time.Duration {
+	return parseDurationDefault(s, 24*time.Hour)
+}
+
+func parseDurationDefault(s string, defaultValue time.Duration) time.Duration {
 	d, err := time.ParseDuration(s)
 	if err != nil {
-		return 24 * time.Hour
+		return defaultValue
 	}
 	return d
 }
@@ -23,7 +23,7 @@ const docTemplate = `{
     "paths": {
         "/health": {
             "get": {
-                "description": "Check if the service is working",
+                "description": "Check if the service is working. ` + "`" + `gazetteer` + "`" + ` is \"partial\" when only the bundled seed list of places is loaded",
                 "produces": [
                     "application/json"
                 ],
@@ -44,14 +44,321 @@ const docTemplate = `{
                 }
             }
         },
-        "/validate-address": {
+        "/v1/autocomplete": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns up to limit address suggestions for a partial address. Queries shorter than 3 characters return no suggestions, and results are cached per prefix so that following keystrokes are usually answered from cache",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "address"
+                ],
+                "summary": "Autocomplete an address while the user types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Partial address",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of suggestions (default 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AutocompleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.AutocompleteResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.AutocompleteResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receives a CSV file with an \"address\" column (and an optional \"id\" column) and validates every row in the background. Poll the job to follow its progress and download the result CSV when it is completed",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Create a bulk validation job",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file with an address column",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Reject rows whose confidence is below this value (0-1)",
+                        "name": "min_confidence",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Content-Type must be multipart/form-data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the status, progress and counters of a bulk validation job. A job is only found with a token of the tenant that created it. A job whose worker stopped (no heartbeat for 2 minutes) is reported as failed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get a bulk validation job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/{id}/result": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the input CSV with the validated address columns, corrections and errors appended to each row. A job is only found with a token of the tenant that created it",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Download the result of a bulk validation job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.JobResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/reverse-geocode": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receives a latitude/longitude pair and returns the nearest normalized address. Results are cached by coordinates rounded to 4 decimal places",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "address"
+                ],
+                "summary": "Find the nearest address to a coordinate",
+                "parameters": [
+                    {
+                        "description": "Coordinates to reverse geocode",
+                        "name": "coordinates",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReverseGeocodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReverseGeocodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReverseGeocodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Content-Type must be application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReverseGeocodeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/validate-address": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Receives a free-form address, corrects typos automatically and returns the normalized components",
+                "description": "Receives a free-form address, corrects typos automatically and returns the normalized components. Corrections are returned as text; use /v2 for structured corrections",
                 "consumes": [
                     "application/json"
                 ],
@@ -77,13 +384,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                            "$ref": "#/definitions/models.ValidateAddressResponseV1"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                            "$ref": "#/definitions/models.ValidateAddressResponseV1"
                         }
                     },
                     "401": {
@@ -104,35 +411,765 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ValidateAddressResponse"
-                        }
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponseV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/validate-address/structured": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receives the address already split into fields. Corrections are applied per field (street typos on the lines, city typos on the city, state names on the state) and the components are sent as structured parameters to providers that support them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "address"
+                ],
+                "summary": "Validate and normalize an address given by components",
+                "parameters": [
+                    {
+                        "description": "Address components to validate",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateStructuredAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponseV1"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Content-Type must be application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponseV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/validate-addresses": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receives a list of free-form addresses with client-supplied IDs and validates them concurrently. Results are returned in the same order as the input, each one with its own status and error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "address"
+                ],
+                "summary": "Validate and normalize a batch of addresses",
+                "parameters": [
+                    {
+                        "description": "Addresses to validate",
+                        "name": "addresses",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchValidateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchValidateResponseV1"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchValidateResponseV1"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Content-Type must be application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/validate-address": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Receives a free-form address, corrects typos automatically and returns the normalized components. Each correction carries the original and replacement text, its kind, token index, character offsets in the input and edit distance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "address"
+                ],
+                "summary": "Validate and normalize an address",
+                "parameters": [
+                    {
+                        "description": "Address to validate",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Content-Type must be application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/validate-address/structured": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Same as the v1 endpoint, with structured corrections. The field of each correction says which component it was made in and its offsets are relative to that field",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "address"
+                ],
+                "summary": "Validate and normalize an address given by components",
+                "parameters": [
+                    {
+                        "description": "Address components to validate",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateStructuredAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Content-Type must be application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateAddressResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/validate-addresses": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Same as the v1 endpoint, with structured corrections in each result",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "address"
+                ],
+                "summary": "Validate and normalize a batch of addresses",
+                "parameters": [
+                    {
+                        "description": "Addresses to validate",
+                        "name": "addresses",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchValidateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchValidateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchValidateResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Content-Type must be application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AddressData": {
+            "type": "object",
+            "properties": {
+                "bounding_box": {
+                    "$ref": "#/definitions/models.BoundingBox"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "San Francisco"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "United States"
+                },
+                "county": {
+                    "type": "string",
+                    "example": "San Francisco County"
+                },
+                "formatted": {
+                    "type": "string",
+                    "example": "123 Main Street, San Francisco, CA 94102"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 37.7749
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": -122.4194
+                },
+                "number": {
+                    "type": "string",
+                    "example": "123"
+                },
+                "postal_code": {
+                    "type": "string",
+                    "example": "94102"
+                },
+                "postal_code_plus4": {
+                    "type": "string",
+                    "example": "1234"
+                },
+                "precision": {
+                    "type": "string",
+                    "example": "rooftop"
+                },
+                "secondary": {
+                    "type": "string",
+                    "example": "APT 4B"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "CA"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Main Street"
+                }
+            }
+        },
+        "models.AutocompleteResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Failed to autocomplete address"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Suggestion"
+                    }
+                }
+            }
+        },
+        "models.BatchAddressItem": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "123 Main Stret, San Fransisco, CA, 94102"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "crm-1001"
+                }
+            }
+        },
+        "models.BatchValidateRequest": {
+            "type": "object",
+            "required": [
+                "addresses"
+            ],
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchAddressItem"
+                    }
+                },
+                "max_candidates": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "min_confidence": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0,
+                    "example": 0.8
+                }
+            }
+        },
+        "models.BatchValidateResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid request: addresses field is required"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchValidateResult"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "models.BatchValidateResponseV1": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid request: addresses field is required"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchValidateResultV1"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "models.BatchValidateResult": {
+            "type": "object",
+            "properties": {
+                "address_type": {
+                    "type": "string",
+                    "example": "street"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Candidate"
+                    }
+                },
+                "confidence": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "corrections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Correction"
+                    }
+                },
+                "data": {
+                    "$ref": "#/definitions/models.AddressData"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Failed to validate address"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "crm-1001"
+                },
+                "parsed": {
+                    "$ref": "#/definitions/models.ParsedAddress"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "verdict": {
+                    "type": "string",
+                    "example": "verified"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Warning"
+                    }
+                }
+            }
+        },
+        "models.BatchValidateResultV1": {
+            "type": "object",
+            "properties": {
+                "address_type": {
+                    "type": "string",
+                    "example": "street"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Candidate"
+                    }
+                },
+                "confidence": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "corrections": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Stret → street (typo correction)",
+                        "Fransisco → francisco (city correction)"
+                    ]
+                },
+                "data": {
+                    "$ref": "#/definitions/models.AddressData"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Failed to validate address"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "crm-1001"
+                },
+                "parsed": {
+                    "$ref": "#/definitions/models.ParsedAddress"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "verdict": {
+                    "type": "string",
+                    "example": "verified"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Warning"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AddressData": {
+        },
+        "models.BoundingBox": {
             "type": "object",
             "properties": {
-                "city": {
+                "max_latitude": {
+                    "type": "number",
+                    "example": 37.8324
+                },
+                "max_longitude": {
+                    "type": "number",
+                    "example": -122.3482
+                },
+                "min_latitude": {
+                    "type": "number",
+                    "example": 37.7034
+                },
+                "min_longitude": {
+                    "type": "number",
+                    "example": -122.527
+                }
+            }
+        },
+        "models.Candidate": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/models.AddressData"
+                },
+                "provider": {
                     "type": "string",
-                    "example": "San Francisco"
+                    "example": "geoapify"
                 },
-                "country": {
+                "score": {
+                    "type": "number",
+                    "example": 0.95
+                }
+            }
+        },
+        "models.Correction": {
+            "type": "object",
+            "properties": {
+                "edit_distance": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "end": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "field": {
                     "type": "string",
-                    "example": "United States"
+                    "example": "line1"
                 },
-                "county": {
+                "kind": {
                     "type": "string",
-                    "example": "San Francisco County"
+                    "example": "typo"
                 },
-                "formatted": {
+                "original": {
                     "type": "string",
-                    "example": "123 Main Street, San Francisco, CA 94102"
+                    "example": "Stret"
+                },
+                "replacement": {
+                    "type": "string",
+                    "example": "street"
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "strategy": {
+                    "type": "string",
+                    "example": "edit_distance"
+                },
+                "token_index": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.Job": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f1c9a8e2b7d4c3a9e0f6b1d2c3a4e5f"
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 250
+                },
+                "progress": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "result_url": {
+                    "type": "string",
+                    "example": "/api/v1/jobs/5f1c9a8e2b7d4c3a9e0f6b1d2c3a4e5f/result"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "tenant": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Job not found"
+                },
+                "job": {
+                    "$ref": "#/definitions/models.Job"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "models.ParsedAddress": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "San Francisco"
                 },
                 "number": {
                     "type": "string",
@@ -142,13 +1179,90 @@ const docTemplate = `{
                     "type": "string",
                     "example": "94102"
                 },
+                "postal_code_plus4": {
+                    "type": "string",
+                    "example": "1234"
+                },
+                "postdirectional": {
+                    "type": "string",
+                    "example": "NW"
+                },
+                "predirectional": {
+                    "type": "string",
+                    "example": "N"
+                },
                 "state": {
                     "type": "string",
                     "example": "CA"
                 },
-                "street": {
+                "street_name": {
                     "type": "string",
-                    "example": "Main Street"
+                    "example": "Main"
+                },
+                "suffix": {
+                    "type": "string",
+                    "example": "ST"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "APT 4B"
+                }
+            }
+        },
+        "models.ReverseGeocodeRequest": {
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude"
+            ],
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 37.7749
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": -122.4194
+                }
+            }
+        },
+        "models.ReverseGeocodeResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.AddressData"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Failed to reverse geocode coordinates"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "geoapify"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "models.Suggestion": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/models.AddressData"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "smarty"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "123 Main St, San Francisco, CA 94102"
                 }
             }
         },
@@ -161,12 +1275,86 @@ const docTemplate = `{
                 "address": {
                     "type": "string",
                     "example": "123 Main Stret, San Fransisco, CA, 94102"
+                },
+                "max_candidates": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "min_confidence": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0,
+                    "example": 0.8
                 }
             }
         },
         "models.ValidateAddressResponse": {
             "type": "object",
             "properties": {
+                "address_type": {
+                    "type": "string",
+                    "example": "street"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Candidate"
+                    }
+                },
+                "confidence": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "corrections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Correction"
+                    }
+                },
+                "data": {
+                    "$ref": "#/definitions/models.AddressData"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Failed to validate address"
+                },
+                "parsed": {
+                    "$ref": "#/definitions/models.ParsedAddress"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "verdict": {
+                    "type": "string",
+                    "example": "verified"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Warning"
+                    }
+                }
+            }
+        },
+        "models.ValidateAddressResponseV1": {
+            "type": "object",
+            "properties": {
+                "address_type": {
+                    "type": "string",
+                    "example": "street"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Candidate"
+                    }
+                },
+                "confidence": {
+                    "type": "number",
+                    "example": 0.95
+                },
                 "corrections": {
                     "type": "array",
                     "items": {
@@ -184,9 +1372,87 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Failed to validate address"
                 },
+                "parsed": {
+                    "$ref": "#/definitions/models.ParsedAddress"
+                },
                 "status": {
                     "type": "string",
                     "example": "success"
+                },
+                "verdict": {
+                    "type": "string",
+                    "example": "verified"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Warning"
+                    }
+                }
+            }
+        },
+        "models.ValidateStructuredAddressRequest": {
+            "type": "object",
+            "required": [
+                "line1"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "San Fransisco"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "United States"
+                },
+                "line1": {
+                    "type": "string",
+                    "example": "123 Main Stret"
+                },
+                "line2": {
+                    "type": "string",
+                    "example": "Apt 4B"
+                },
+                "max_candidates": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "min_confidence": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0,
+                    "example": 0.8
+                },
+                "postal_code": {
+                    "type": "string",
+                    "example": "94102"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "California"
+                }
+            }
+        },
+        "models.Warning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "STATE_ZIP_MISMATCH"
+                },
+                "confidence": {
+                    "description": "Confidence is \"low\" when the check relied on incomplete data.",
+                    "type": "string",
+                    "example": "low"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "postal_code"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ZIP code 10001 belongs to NY, not CA"
                 }
             }
         }
@@ -205,7 +1471,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "localhost:3000",
-	BasePath:         "/api/v1",
+	BasePath:         "/api",
 	Schemes:          []string{},
 	Title:            "Address Validator API",
 	Description:      "API to validate and normalize addresses in free-form text with automatic typo corrections.\nThe API accepts addresses entered naturally by the user and returns normalized components.",
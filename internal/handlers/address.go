@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,17 +11,39 @@ import (
 
 type AddressHandler struct {
 	validatorService *services.ValidatorService
+	batchMaxSize     int
+	batchWorkers     int
 }
 
-func NewAddressHandler(validatorService *services.ValidatorService) *AddressHandler {
+func NewAddressHandler(validatorService *services.ValidatorService, batchMaxSize, batchWorkers int) *AddressHandler {
 	return &AddressHandler{
 		validatorService: validatorService,
+		batchMaxSize:     batchMaxSize,
+		batchWorkers:     batchWorkers,
 	}
 }
 
 // ValidateAddress godoc
 // @Summary      Validate and normalize an address
-// @Description  Receives a free-form address, corrects typos automatically and returns the normalized components
+// @Description  Receives a free-form address, corrects typos automatically and returns the normalized components. Corrections are returned as text; use /v2 for structured corrections
+// @Tags         address
+// @Accept       json
+// @Produce      json
+// @Param        address  body      models.ValidateAddressRequest  true  "Address to validate"
+// @Success      200      {object}  models.ValidateAddressResponseV1
+// @Failure      400      {object}  models.ValidateAddressResponseV1
+// @Failure      401      {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      415      {object}  map[string]string "Unsupported Media Type - Content-Type must be application/json"
+// @Failure      500      {object}  models.ValidateAddressResponseV1
+// @Security     BearerAuth
+// @Router       /v1/validate-address [post]
+func (h *AddressHandler) ValidateAddress(c *gin.Context) {
+	h.validateAddress(c, responseV1)
+}
+
+// ValidateAddressV2 godoc
+// @Summary      Validate and normalize an address
+// @Description  Receives a free-form address, corrects typos automatically and returns the normalized components. Each correction carries the original and replacement text, its kind, token index, character offsets in the input and edit distance
 // @Tags         address
 // @Accept       json
 // @Produce      json
@@ -31,19 +54,82 @@ func NewAddressHandler(validatorService *services.ValidatorService) *AddressHand
 // @Failure      415      {object}  map[string]string "Unsupported Media Type - Content-Type must be application/json"
 // @Failure      500      {object}  models.ValidateAddressResponse
 // @Security     BearerAuth
-// @Router       /validate-address [post]
-func (h *AddressHandler) ValidateAddress(c *gin.Context) {
+// @Router       /v2/validate-address [post]
+func (h *AddressHandler) ValidateAddressV2(c *gin.Context) {
+	h.validateAddress(c, responseV2)
+}
+
+func (h *AddressHandler) validateAddress(c *gin.Context, render func(*models.ValidateAddressResponse) any) {
 	var req models.ValidateAddressRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ValidateAddressResponse{
 			Status: "error",
-			Error:  "Invalid request: address field is required",
+			Error:  "Invalid request: address field is required and min_confidence must be between 0 and 1",
+		})
+		return
+	}
+
+	result, err := h.validatorService.ValidateAddress(c.Request.Context(), req.Address, req.ValidationOptions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ValidateAddressResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, render(result))
+}
+
+// ValidateStructuredAddress godoc
+// @Summary      Validate and normalize an address given by components
+// @Description  Receives the address already split into fields. Corrections are applied per field (street typos on the lines, city typos on the city, state names on the state) and the components are sent as structured parameters to providers that support them
+// @Tags         address
+// @Accept       json
+// @Produce      json
+// @Param        address  body      models.ValidateStructuredAddressRequest  true  "Address components to validate"
+// @Success      200      {object}  models.ValidateAddressResponseV1
+// @Failure      400      {object}  models.ValidateAddressResponseV1
+// @Failure      401      {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      415      {object}  map[string]string "Unsupported Media Type - Content-Type must be application/json"
+// @Failure      500      {object}  models.ValidateAddressResponseV1
+// @Security     BearerAuth
+// @Router       /v1/validate-address/structured [post]
+func (h *AddressHandler) ValidateStructuredAddress(c *gin.Context) {
+	h.validateStructuredAddress(c, responseV1)
+}
+
+// ValidateStructuredAddressV2 godoc
+// @Summary      Validate and normalize an address given by components
+// @Description  Same as the v1 endpoint, with structured corrections. The field of each correction says which component it was made in and its offsets are relative to that field
+// @Tags         address
+// @Accept       json
+// @Produce      json
+// @Param        address  body      models.ValidateStructuredAddressRequest  true  "Address components to validate"
+// @Success      200      {object}  models.ValidateAddressResponse
+// @Failure      400      {object}  models.ValidateAddressResponse
+// @Failure      401      {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      415      {object}  map[string]string "Unsupported Media Type - Content-Type must be application/json"
+// @Failure      500      {object}  models.ValidateAddressResponse
+// @Security     BearerAuth
+// @Router       /v2/validate-address/structured [post]
+func (h *AddressHandler) ValidateStructuredAddressV2(c *gin.Context) {
+	h.validateStructuredAddress(c, responseV2)
+}
+
+func (h *AddressHandler) validateStructuredAddress(c *gin.Context, render func(*models.ValidateAddressResponse) any) {
+	var req models.ValidateStructuredAddressRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidateAddressResponse{
+			Status: "error",
+			Error:  "Invalid request: line1 field is required and min_confidence must be between 0 and 1",
 		})
 		return
 	}
 
-	result, err := h.validatorService.ValidateAddress(c.Request.Context(), req.Address)
+	result, err := h.validatorService.ValidateStructuredAddress(c.Request.Context(), req.StructuredAddress, req.ValidationOptions)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ValidateAddressResponse{
 			Status: "error",
@@ -52,5 +138,122 @@ func (h *AddressHandler) ValidateAddress(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, render(result))
+}
+
+// ValidateAddresses godoc
+// @Summary      Validate and normalize a batch of addresses
+// @Description  Receives a list of free-form addresses with client-supplied IDs and validates them concurrently. Results are returned in the same order as the input, each one with its own status and error
+// @Tags         address
+// @Accept       json
+// @Produce      json
+// @Param        addresses  body      models.BatchValidateRequest  true  "Addresses to validate"
+// @Success      200        {object}  models.BatchValidateResponseV1
+// @Failure      400        {object}  models.BatchValidateResponseV1
+// @Failure      401        {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      415        {object}  map[string]string "Unsupported Media Type - Content-Type must be application/json"
+// @Security     BearerAuth
+// @Router       /v1/validate-addresses [post]
+func (h *AddressHandler) ValidateAddresses(c *gin.Context) {
+	results, ok := h.validateAddresses(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BatchValidateResponseV1{
+		Status:  "success",
+		Results: services.BatchResultsV1(results),
+	})
+}
+
+// ValidateAddressesV2 godoc
+// @Summary      Validate and normalize a batch of addresses
+// @Description  Same as the v1 endpoint, with structured corrections in each result
+// @Tags         address
+// @Accept       json
+// @Produce      json
+// @Param        addresses  body      models.BatchValidateRequest  true  "Addresses to validate"
+// @Success      200        {object}  models.BatchValidateResponse
+// @Failure      400        {object}  models.BatchValidateResponse
+// @Failure      401        {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      415        {object}  map[string]string "Unsupported Media Type - Content-Type must be application/json"
+// @Security     BearerAuth
+// @Router       /v2/validate-addresses [post]
+func (h *AddressHandler) ValidateAddressesV2(c *gin.Context) {
+	results, ok := h.validateAddresses(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BatchValidateResponse{
+		Status:  "success",
+		Results: results,
+	})
+}
+
+func (h *AddressHandler) validateAddresses(c *gin.Context) ([]models.BatchValidateResult, bool) {
+	var req models.BatchValidateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Addresses) == 0 {
+		c.JSON(http.StatusBadRequest, models.BatchValidateResponse{
+			Status: "error",
+			Error:  "Invalid request: addresses field is required and min_confidence must be between 0 and 1",
+		})
+		return nil, false
+	}
+
+	if len(req.Addresses) > h.batchMaxSize {
+		c.JSON(http.StatusBadRequest, models.BatchValidateResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("Invalid request: at most %d addresses are allowed per batch", h.batchMaxSize),
+		})
+		return nil, false
+	}
+
+	return h.validatorService.ValidateAddresses(c.Request.Context(), req.Addresses, req.ValidationOptions, h.batchWorkers), true
+}
+
+// ReverseGeocode godoc
+// @Summary      Find the nearest address to a coordinate
+// @Description  Receives a latitude/longitude pair and returns the nearest normalized address. Results are cached by coordinates rounded to 4 decimal places
+// @Tags         address
+// @Accept       json
+// @Produce      json
+// @Param        coordinates  body      models.ReverseGeocodeRequest  true  "Coordinates to reverse geocode"
+// @Success      200          {object}  models.ReverseGeocodeResponse
+// @Failure      400          {object}  models.ReverseGeocodeResponse
+// @Failure      401          {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      415          {object}  map[string]string "Unsupported Media Type - Content-Type must be application/json"
+// @Failure      500          {object}  models.ReverseGeocodeResponse
+// @Security     BearerAuth
+// @Router       /v1/reverse-geocode [post]
+func (h *AddressHandler) ReverseGeocode(c *gin.Context) {
+	var req models.ReverseGeocodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ReverseGeocodeResponse{
+			Status: "error",
+			Error:  "Invalid request: latitude must be between -90 and 90 and longitude between -180 and 180",
+		})
+		return
+	}
+
+	result, err := h.validatorService.ReverseGeocode(c.Request.Context(), *req.Latitude, *req.Longitude)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ReverseGeocodeResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, result)
 }
+
+func responseV1(response *models.ValidateAddressResponse) any {
+	return services.ResponseV1(response)
+}
+
+func responseV2(response *models.ValidateAddressResponse) any {
+	return response
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/henrique/address-validator/internal/models"
+	"github.com/henrique/address-validator/internal/services"
+)
+
+const defaultAutocompleteLimit = 5
+
+type AutocompleteHandler struct {
+	autocompleteService *services.AutocompleteService
+	maxLimit            int
+}
+
+func NewAutocompleteHandler(autocompleteService *services.AutocompleteService, maxLimit int) *AutocompleteHandler {
+	return &AutocompleteHandler{
+		autocompleteService: autocompleteService,
+		maxLimit:            maxLimit,
+	}
+}
+
+// Autocomplete godoc
+// @Summary      Autocomplete an address while the user types
+// @Description  Returns up to limit address suggestions for a partial address. Queries shorter than 3 characters return no suggestions, and results are cached per prefix so that following keystrokes are usually answered from cache
+// @Tags         address
+// @Produce      json
+// @Param        q      query     string   true   "Partial address"
+// @Param        limit  query     integer  false  "Maximum number of suggestions (default 5)"
+// @Success      200    {object}  models.AutocompleteResponse
+// @Failure      400    {object}  models.AutocompleteResponse
+// @Failure      401    {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      502    {object}  models.AutocompleteResponse
+// @Security     BearerAuth
+// @Router       /v1/autocomplete [get]
+func (h *AutocompleteHandler) Autocomplete(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.AutocompleteResponse{
+			Status:      "error",
+			Suggestions: []models.Suggestion{},
+			Error:       "Invalid request: q parameter is required",
+		})
+		return
+	}
+
+	limit := defaultAutocompleteLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > h.maxLimit {
+			c.JSON(http.StatusBadRequest, models.AutocompleteResponse{
+				Status:      "error",
+				Suggestions: []models.Suggestion{},
+				Error:       fmt.Sprintf("Invalid request: limit must be between 1 and %d", h.maxLimit),
+			})
+			return
+		}
+		limit = parsed
+	}
+	if limit > h.maxLimit {
+		limit = h.maxLimit
+	}
+
+	result, err := h.autocompleteService.Autocomplete(c.Request.Context(), query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.AutocompleteResponse{
+			Status:      "error",
+			Suggestions: []models.Suggestion{},
+			Error:       err.Error(),
+		})
+		return
+	}
+
+	if result.Status != "success" {
+		c.JSON(http.StatusBadGateway, result)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=60")
+	c.JSON(http.StatusOK, result)
+}
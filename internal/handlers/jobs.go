@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/henrique/address-validator/internal/models"
+	"github.com/henrique/address-validator/internal/services"
+)
+
+type JobHandler struct {
+	jobService    *services.JobService
+	maxUploadSize int64
+}
+
+func NewJobHandler(jobService *services.JobService, maxUploadSize int64) *JobHandler {
+	return &JobHandler{
+		jobService:    jobService,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// CreateJob godoc
+// @Summary      Create a bulk validation job
+// @Description  Receives a CSV file with an "address" column (and an optional "id" column) and validates every row in the background. Poll the job to follow its progress and download the result CSV when it is completed
+// @Tags         jobs
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file            formData  file    true   "CSV file with an address column"
+// @Param        min_confidence  formData  number  false  "Reject rows whose confidence is below this value (0-1)"
+// @Success      202   {object}  models.JobResponse
+// @Failure      400   {object}  models.JobResponse
+// @Failure      401   {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      413   {object}  models.JobResponse
+// @Failure      415   {object}  map[string]string "Unsupported Media Type - Content-Type must be multipart/form-data"
+// @Security     BearerAuth
+// @Router       /v1/jobs [post]
+func (h *JobHandler) CreateJob(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, models.JobResponse{
+				Status: "error",
+				Error:  fmt.Sprintf("CSV file must be at most %d bytes", h.maxUploadSize),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, models.JobResponse{
+			Status: "error",
+			Error:  "Invalid request: file field is required",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.JobResponse{
+			Status: "error",
+			Error:  "Invalid request: unable to read file",
+		})
+		return
+	}
+	defer file.Close()
+
+	var opts models.ValidationOptions
+	if value := c.PostForm("min_confidence"); value != "" {
+		minConfidence, err := strconv.ParseFloat(value, 64)
+		if err != nil || minConfidence < 0 || minConfidence > 1 {
+			c.JSON(http.StatusBadRequest, models.JobResponse{
+				Status: "error",
+				Error:  "Invalid request: min_confidence must be between 0 and 1",
+			})
+			return
+		}
+		opts.MinConfidence = minConfidence
+	}
+
+	job, err := h.jobService.CreateJob(c.Request.Context(), file, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.JobResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.JobResponse{
+		Status: "success",
+		Job:    job,
+	})
+}
+
+// GetJob godoc
+// @Summary      Get a bulk validation job
+// @Description  Returns the status, progress and counters of a bulk validation job. A job is only found with a token of the tenant that created it. A job whose worker stopped (no heartbeat for 2 minutes) is reported as failed
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  models.JobResponse
+// @Failure      401  {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      404  {object}  models.JobResponse
+// @Security     BearerAuth
+// @Router       /v1/jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.JobResponse{
+			Status: "error",
+			Error:  "Job not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.JobResponse{
+		Status: "success",
+		Job:    job,
+	})
+}
+
+// GetJobResult godoc
+// @Summary      Download the result of a bulk validation job
+// @Description  Returns the input CSV with the validated address columns, corrections and errors appended to each row. A job is only found with a token of the tenant that created it
+// @Tags         jobs
+// @Produce      text/csv
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {string}  string  "Result CSV"
+// @Failure      401  {object}  map[string]string "Unauthorized - Invalid or missing token"
+// @Failure      404  {object}  models.JobResponse
+// @Failure      409  {object}  models.JobResponse
+// @Security     BearerAuth
+// @Router       /v1/jobs/{id}/result [get]
+func (h *JobHandler) GetJobResult(c *gin.Context) {
+	id := c.Param("id")
+
+	job, err := h.jobService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.JobResponse{
+			Status: "error",
+			Error:  "Job not found",
+		})
+		return
+	}
+
+	if job.Status != services.JobStatusCompleted {
+		c.JSON(http.StatusConflict, models.JobResponse{
+			Status: "error",
+			Job:    job,
+			Error:  "Job is not completed yet",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+".csv"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	if err := h.jobService.WriteResult(c.Request.Context(), id, c.Writer); err != nil {
+		if c.Writer.Written() {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		c.JSON(http.StatusNotFound, models.JobResponse{
+			Status: "error",
+			Error:  "Job result not found",
+		})
+	}
+}
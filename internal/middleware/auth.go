@@ -1,13 +1,23 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func BearerAuth(validToken string) gin.HandlerFunc {
+// TenantLookup finds the tenant of a token other than the main API token and
+// returns the request context carrying it.
+type TenantLookup interface {
+	TenantContext(ctx context.Context, token string) (context.Context, bool)
+}
+
+// BearerAuth accepts the main API token and the tokens of the tenants, if any.
+// The tenant of the token is put in the request context, so its words apply to
+// the normalization.
+func BearerAuth(validToken string, tenants TenantLookup) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 
@@ -41,12 +51,20 @@ func BearerAuth(validToken string) gin.HandlerFunc {
 		}
 
 		if token != validToken {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token",
-				"code":  401,
-			})
-			c.Abort()
-			return
+			var ctx context.Context
+			found := false
+			if tenants != nil {
+				ctx, found = tenants.TenantContext(c.Request.Context(), token)
+			}
+			if !found {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "Invalid token",
+					"code":  401,
+				})
+				c.Abort()
+				return
+			}
+			c.Request = c.Request.WithContext(ctx)
 		}
 
 		c.Next()
@@ -54,13 +72,17 @@ func BearerAuth(validToken string) gin.HandlerFunc {
 }
 
 func ValidateHeaders() gin.HandlerFunc {
+	return ValidateHeadersFor([]string{"application/json"}, []string{"application/json"})
+}
+
+func ValidateHeadersFor(consumes []string, produces []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Method == http.MethodPost || c.Request.Method == http.MethodPut || c.Request.Method == http.MethodPatch {
 			contentType := c.GetHeader("Content-Type")
 
-			if contentType != "application/json" && !strings.HasPrefix(contentType, "application/json;") {
+			if !matchesMediaType(contentType, consumes) {
 				c.JSON(http.StatusUnsupportedMediaType, gin.H{
-					"error": "Content-Type must be application/json",
+					"error": "Content-Type must be " + strings.Join(consumes, " or "),
 					"code":  415,
 				})
 				c.Abort()
@@ -69,9 +91,9 @@ func ValidateHeaders() gin.HandlerFunc {
 		}
 
 		accept := c.GetHeader("Accept")
-		if accept != "" && accept != "*/*" && !strings.Contains(accept, "application/json") {
+		if accept != "" && accept != "*/*" && !acceptsAny(accept, produces) {
 			c.JSON(http.StatusNotAcceptable, gin.H{
-				"error": "Accept header must include application/json",
+				"error": "Accept header must include " + strings.Join(produces, " or "),
 				"code":  406,
 			})
 			c.Abort()
@@ -81,3 +103,21 @@ func ValidateHeaders() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func matchesMediaType(contentType string, mediaTypes []string) bool {
+	for _, mediaType := range mediaTypes {
+		if contentType == mediaType || strings.HasPrefix(contentType, mediaType+";") {
+			return true
+		}
+	}
+	return false
+}
+
+func acceptsAny(accept string, mediaTypes []string) bool {
+	for _, mediaType := range mediaTypes {
+		if strings.Contains(accept, mediaType) {
+			return true
+		}
+	}
+	return false
+}
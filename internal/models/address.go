@@ -2,35 +2,191 @@ package models
 
 type ValidateAddressRequest struct {
 	Address string `json:"address" binding:"required" example:"123 Main Stret, San Fransisco, CA, 94102"`
+	ValidationOptions
+}
+
+type ValidateStructuredAddressRequest struct {
+	StructuredAddress
+	ValidationOptions
+}
+
+type StructuredAddress struct {
+	Line1      string `json:"line1" binding:"required" example:"123 Main Stret"`
+	Line2      string `json:"line2,omitempty" example:"Apt 4B"`
+	City       string `json:"city,omitempty" example:"San Fransisco"`
+	State      string `json:"state,omitempty" example:"California"`
+	PostalCode string `json:"postal_code,omitempty" example:"94102"`
+	Country    string `json:"country,omitempty" example:"United States"`
+}
+
+type ValidationOptions struct {
+	MinConfidence float64 `json:"min_confidence,omitempty" binding:"min=0,max=1" example:"0.8"`
+	MaxCandidates int     `json:"max_candidates,omitempty" binding:"min=0" example:"3"`
 }
 
 type ValidateAddressResponse struct {
-	Status      string       `json:"status" example:"success"`
-	Data        *AddressData `json:"data,omitempty"`
-	Corrections []string     `json:"corrections,omitempty" example:"Stret → street (typo correction),Fransisco → francisco (city correction)"`
-	Error       string       `json:"error,omitempty" example:"Failed to validate address"`
+	Status      string         `json:"status" example:"success"`
+	Data        *AddressData   `json:"data,omitempty"`
+	Confidence  float64        `json:"confidence" example:"0.95"`
+	Verdict     string         `json:"verdict,omitempty" example:"verified"`
+	AddressType string         `json:"address_type,omitempty" example:"street"`
+	Candidates  []Candidate    `json:"candidates,omitempty"`
+	Parsed      *ParsedAddress `json:"parsed,omitempty"`
+	Corrections []Correction   `json:"corrections,omitempty"`
+	Warnings    []Warning      `json:"warnings,omitempty"`
+	Error       string         `json:"error,omitempty" example:"Failed to validate address"`
+}
+
+// ValidateAddressResponseV1 is the /api/v1 form of the response, with each
+// correction as text ("Stret → street (typo correction)").
+type ValidateAddressResponseV1 struct {
+	ValidateAddressResponse
+	Corrections []string `json:"corrections,omitempty" example:"Stret → street (typo correction),Fransisco → francisco (city correction)"`
+}
+
+// Correction is a change made by the normalizer. Start and End are character
+// offsets in the input (or in Field, for structured input) and TokenIndex is the
+// index of the first whitespace-separated word changed. Strategy is set on
+// corrections found by fuzzy matching.
+type Correction struct {
+	Original     string  `json:"original" example:"Stret"`
+	Replacement  string  `json:"replacement" example:"street"`
+	Kind         string  `json:"kind" example:"typo"`
+	Strategy     string  `json:"strategy,omitempty" example:"edit_distance"`
+	Field        string  `json:"field,omitempty" example:"line1"`
+	TokenIndex   int     `json:"token_index" example:"2"`
+	Start        int     `json:"start" example:"9"`
+	End          int     `json:"end" example:"14"`
+	EditDistance float64 `json:"edit_distance" example:"0.5"`
+}
+
+// ParsedAddress holds the components found in the input by the local parser,
+// with directionals and suffixes in USPS standard abbreviations.
+type ParsedAddress struct {
+	Number          string `json:"number,omitempty" example:"123"`
+	PreDirection    string `json:"predirectional,omitempty" example:"N"`
+	StreetName      string `json:"street_name,omitempty" example:"Main"`
+	Suffix          string `json:"suffix,omitempty" example:"ST"`
+	PostDirection   string `json:"postdirectional,omitempty" example:"NW"`
+	Unit            string `json:"unit,omitempty" example:"APT 4B"`
+	City            string `json:"city,omitempty" example:"San Francisco"`
+	State           string `json:"state,omitempty" example:"CA"`
+	PostalCode      string `json:"postal_code,omitempty" example:"94102"`
+	PostalCodePlus4 string `json:"postal_code_plus4,omitempty" example:"1234"`
+}
+
+type Warning struct {
+	Code    string `json:"code" example:"STATE_ZIP_MISMATCH"`
+	Message string `json:"message" example:"ZIP code 10001 belongs to NY, not CA"`
+	Field   string `json:"field,omitempty" example:"postal_code"`
+	// Confidence is "low" when the check relied on incomplete data.
+	Confidence string `json:"confidence,omitempty" example:"low"`
+}
+
+type BatchValidateRequest struct {
+	Addresses []BatchAddressItem `json:"addresses" binding:"required"`
+	ValidationOptions
+}
+
+type BatchAddressItem struct {
+	ID      string `json:"id" example:"crm-1001"`
+	Address string `json:"address" example:"123 Main Stret, San Fransisco, CA, 94102"`
+}
+
+type BatchValidateResponse struct {
+	Status  string                `json:"status" example:"success"`
+	Results []BatchValidateResult `json:"results,omitempty"`
+	Error   string                `json:"error,omitempty" example:"Invalid request: addresses field is required"`
+}
+
+type BatchValidateResult struct {
+	ID string `json:"id" example:"crm-1001"`
+	ValidateAddressResponse
+}
+
+type BatchValidateResponseV1 struct {
+	Status  string                  `json:"status" example:"success"`
+	Results []BatchValidateResultV1 `json:"results,omitempty"`
+	Error   string                  `json:"error,omitempty" example:"Invalid request: addresses field is required"`
+}
+
+type BatchValidateResultV1 struct {
+	ID string `json:"id" example:"crm-1001"`
+	ValidateAddressResponseV1
 }
 
 type AddressData struct {
-	Street     string `json:"street" example:"Main Street"`
-	Number     string `json:"number" example:"123"`
-	City       string `json:"city" example:"San Francisco"`
-	State      string `json:"state" example:"CA"`
-	PostalCode string `json:"postal_code" example:"94102"`
-	County     string `json:"county,omitempty" example:"San Francisco County"`
-	Country    string `json:"country" example:"United States"`
-	Formatted  string `json:"formatted" example:"123 Main Street, San Francisco, CA 94102"`
+	Street          string       `json:"street" example:"Main Street"`
+	Number          string       `json:"number" example:"123"`
+	Secondary       string       `json:"secondary,omitempty" example:"APT 4B"`
+	City            string       `json:"city" example:"San Francisco"`
+	State           string       `json:"state" example:"CA"`
+	PostalCode      string       `json:"postal_code" example:"94102"`
+	PostalCodePlus4 string       `json:"postal_code_plus4,omitempty" example:"1234"`
+	County          string       `json:"county,omitempty" example:"San Francisco County"`
+	Country         string       `json:"country" example:"United States"`
+	Formatted       string       `json:"formatted" example:"123 Main Street, San Francisco, CA 94102"`
+	Latitude        *float64     `json:"latitude,omitempty" example:"37.7749"`
+	Longitude       *float64     `json:"longitude,omitempty" example:"-122.4194"`
+	Precision       string       `json:"precision,omitempty" example:"rooftop"`
+	BoundingBox     *BoundingBox `json:"bounding_box,omitempty"`
+}
+
+type BoundingBox struct {
+	MinLatitude  float64 `json:"min_latitude" example:"37.7034"`
+	MinLongitude float64 `json:"min_longitude" example:"-122.5270"`
+	MaxLatitude  float64 `json:"max_latitude" example:"37.8324"`
+	MaxLongitude float64 `json:"max_longitude" example:"-122.3482"`
+}
+
+type Candidate struct {
+	Address  *AddressData `json:"address"`
+	Provider string       `json:"provider" example:"geoapify"`
+	Score    float64      `json:"score" example:"0.95"`
+}
+
+type Suggestion struct {
+	Text     string       `json:"text" example:"123 Main St, San Francisco, CA 94102"`
+	Address  *AddressData `json:"address"`
+	Provider string       `json:"provider" example:"smarty"`
+}
+
+type AutocompleteResponse struct {
+	Status      string       `json:"status" example:"success"`
+	Suggestions []Suggestion `json:"suggestions"`
+	Error       string       `json:"error,omitempty" example:"Failed to autocomplete address"`
+}
+
+type ReverseGeocodeRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90" example:"37.7749"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180" example:"-122.4194"`
+}
+
+type ReverseGeocodeResponse struct {
+	Status   string       `json:"status" example:"success"`
+	Data     *AddressData `json:"data,omitempty"`
+	Provider string       `json:"provider,omitempty" example:"geoapify"`
+	Error    string       `json:"error,omitempty" example:"Failed to reverse geocode coordinates"`
 }
 
 type GeocodingResponse struct {
 	Success     bool
 	AddressData *AddressData
+	Candidates  []Candidate
 	Provider    string
 	Error       error
 }
 
 type NormalizedInput struct {
-	Original   string
-	Normalized string
-	Changes    []string
+	Original        string
+	Normalized      string
+	AddressType     string
+	DeliveryLine    string
+	Secondary       string
+	City            string
+	State           string
+	PostalCode      string
+	PostalCodePlus4 string
+	Corrections     []Correction
+	Warnings        []Warning
 }
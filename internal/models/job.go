@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type Job struct {
+	ID        string    `json:"id" example:"5f1c9a8e2b7d4c3a9e0f6b1d2c3a4e5f"`
+	Tenant    string    `json:"tenant,omitempty" example:"acme"`
+	Status    string    `json:"status" example:"running"`
+	Total     int       `json:"total" example:"1000"`
+	Processed int       `json:"processed" example:"250"`
+	Succeeded int       `json:"succeeded" example:"240"`
+	Failed    int       `json:"failed" example:"10"`
+	Progress  float64   `json:"progress" example:"0.25"`
+	ResultURL string    `json:"result_url,omitempty" example:"/api/v1/jobs/5f1c9a8e2b7d4c3a9e0f6b1d2c3a4e5f/result"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type JobResponse struct {
+	Status string `json:"status" example:"success"`
+	Job    *Job   `json:"job,omitempty"`
+	Error  string `json:"error,omitempty" example:"Job not found"`
+}
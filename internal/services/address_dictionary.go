@@ -1,9 +1,12 @@
 package services
 
 import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
 	"strings"
-
-	"github.com/agnivade/levenshtein"
 )
 
 var USStates = map[string]string{
@@ -38,16 +41,10 @@ var StateAbbreviations = map[string]string{
 	"wisconsin": "WI", "wyoming": "WY", "district of columbia": "DC",
 }
 
-var CommonStreetTypes = []string{
-	"street", "avenue", "boulevard", "road", "drive", "lane", "court",
-	"place", "way", "circle", "parkway", "terrace", "trail", "highway",
-	"plaza", "alley", "bridge", "expressway", "freeway", "walk", "square",
-}
-
 var CommonCityNames = []string{
 	"new york", "los angeles", "chicago", "houston", "phoenix", "philadelphia",
 	"san antonio", "san diego", "dallas", "san jose", "austin", "jacksonville",
-	"fort worth", "columbus", "charlotte", "francisco", "indianapolis", "seattle",
+	"fort worth", "columbus", "charlotte", "san francisco", "francisco", "indianapolis", "seattle",
 	"denver", "washington", "boston", "el paso", "nashville", "detroit", "oklahoma",
 	"portland", "las vegas", "memphis", "louisville", "baltimore", "milwaukee",
 	"albuquerque", "tucson", "fresno", "mesa", "sacramento", "atlanta", "kansas",
@@ -55,26 +52,33 @@ var CommonCityNames = []string{
 	"oakland", "minneapolis", "tulsa", "tampa", "arlington", "new orleans",
 }
 
-var StreetAbbreviations = map[string]string{
-	"st": "street", "st.": "street",
-	"ave": "avenue", "ave.": "avenue", "av": "avenue",
-	"blvd": "boulevard", "blvd.": "boulevard",
-	"rd": "road", "rd.": "road",
-	"dr": "drive", "dr.": "drive",
-	"ln": "lane", "ln.": "lane",
-	"ct": "court", "ct.": "court",
-	"pl": "place", "pl.": "place",
-	"pkwy": "parkway", "pkwy.": "parkway",
-	"ter": "terrace", "ter.": "terrace",
-	"trl": "trail", "trl.": "trail",
-	"hwy": "highway", "hwy.": "highway",
-	"cir": "circle", "cir.": "circle",
-	"sq": "square", "sq.": "square",
-	"aly": "alley", "aly.": "alley",
-	"expy": "expressway", "expy.": "expressway",
-	"fwy": "freeway", "fwy.": "freeway",
+// StopWords are never corrected as typos: common street-name words that a
+// single edit turns into a suffix or a city.
+var StopWords = []string{
+	"main", "park", "oak", "pine", "maple", "elm", "cedar", "lake", "hill",
+	"view", "center", "first", "second", "third", "north", "south", "east",
+	"west", "new", "old", "grand", "high", "spring",
+}
+
+// StreetSuffix is a USPS street suffix with its standard abbreviation and the
+// spellings USPS accepts for it.
+type StreetSuffix struct {
+	Name         string   `json:"name"`
+	Abbreviation string   `json:"abbreviation"`
+	Variants     []string `json:"variants"`
 }
 
+//go:embed data/street_suffixes.csv
+var streetSuffixData string
+
+var StreetSuffixes = mustParseStreetSuffixes(streetSuffixData)
+
+var CommonStreetTypes = streetSuffixNames(StreetSuffixes)
+
+var StreetAbbreviations = streetSuffixAbbreviations(StreetSuffixes)
+
+var stateNameDictionary = NewDictionary(stateNames())
+
 var DirectionAbbreviations = map[string]string{
 	"n": "north", "n.": "north",
 	"s": "south", "s.": "south",
@@ -86,14 +90,133 @@ var DirectionAbbreviations = map[string]string{
 	"sw": "southwest", "sw.": "southwest",
 }
 
+// SecondaryUnitDesignators maps the accepted spellings of the USPS secondary unit
+// designators to their standard abbreviation.
+var SecondaryUnitDesignators = map[string]string{
+	"apartment": "APT", "apt": "APT",
+	"basement": "BSMT", "bsmt": "BSMT",
+	"building": "BLDG", "bldg": "BLDG",
+	"department": "DEPT", "dept": "DEPT",
+	"floor": "FL", "fl": "FL",
+	"front": "FRNT", "frnt": "FRNT",
+	"hangar": "HNGR", "hngr": "HNGR",
+	"lobby": "LBBY", "lbby": "LBBY",
+	"lower": "LOWR", "lowr": "LOWR",
+	"office": "OFC", "ofc": "OFC",
+	"penthouse": "PH", "ph": "PH",
+	"room": "RM", "rm": "RM",
+	"space": "SPC", "spc": "SPC",
+	"suite": "STE", "ste": "STE",
+	"trailer": "TRLR", "trlr": "TRLR",
+	"upper": "UPPR", "uppr": "UPPR",
+	"key": "KEY", "lot": "LOT", "pier": "PIER", "rear": "REAR",
+	"side": "SIDE", "slip": "SLIP", "stop": "STOP", "unit": "UNIT",
+	"#": "#",
+}
+
+// RangelessUnitDesignators are the designators USPS allows without a unit number.
+var RangelessUnitDesignators = map[string]bool{
+	"BSMT": true, "FRNT": true, "LBBY": true, "LOWR": true,
+	"OFC": true, "PH": true, "REAR": true, "SIDE": true, "UPPR": true,
+}
+
+// SecondaryUnitNames are the spelled-out designators used for typo correction.
+var SecondaryUnitNames = []string{
+	"apartment", "basement", "building", "department", "hangar",
+	"office", "penthouse", "suite", "trailer",
+}
+
+var unitNameDictionary = NewDictionary(SecondaryUnitNames)
+
+// stateNames lists the full state names in a fixed order, so ties between two
+// equally close names always resolve the same way.
+func stateNames() []string {
+	names := make([]string, 0, len(StateAbbreviations))
+	for name := range StateAbbreviations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func filterMultiWord(names []string) []string {
+	multiWord := []string{}
+	for _, name := range names {
+		if strings.Contains(name, " ") {
+			multiWord = append(multiWord, name)
+		}
+	}
+	return multiWord
+}
+
+func mustParseStreetSuffixes(data string) []StreetSuffix {
+	suffixes, err := parseStreetSuffixes(strings.NewReader(data))
+	if err != nil {
+		panic(fmt.Sprintf("invalid street suffix table: %v", err))
+	}
+	return suffixes
+}
+
+func parseStreetSuffixes(r io.Reader) ([]StreetSuffix, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+
+	suffixes := []StreetSuffix{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		suffixes = append(suffixes, StreetSuffix{
+			Name:         strings.ToLower(strings.TrimSpace(record[0])),
+			Abbreviation: strings.ToUpper(strings.TrimSpace(record[1])),
+			Variants:     strings.Fields(strings.ToLower(record[2])),
+		})
+	}
+	return suffixes, nil
+}
+
+func streetSuffixNames(suffixes []StreetSuffix) []string {
+	names := make([]string, len(suffixes))
+	for i, suffix := range suffixes {
+		names[i] = suffix.Name
+	}
+	return names
+}
+
+// streetSuffixAbbreviations maps every accepted spelling, with and without a
+// trailing period, to the full suffix name.
+func streetSuffixAbbreviations(suffixes []StreetSuffix) map[string]string {
+	abbreviations := make(map[string]string)
+	for _, suffix := range suffixes {
+		for _, variant := range suffix.Variants {
+			if variant == suffix.Name {
+				continue
+			}
+			abbreviations[variant] = suffix.Name
+			abbreviations[variant+"."] = suffix.Name
+		}
+	}
+	return abbreviations
+}
+
+// FindClosestMatch returns the dictionary word with the lowest typo distance,
+// accepting at most maxDistance edits and never more than maxTypoCost allows for
+// the length of the word. It scans the whole list; long lists are better kept in
+// a Dictionary.
 func FindClosestMatch(word string, dictionary []string, maxDistance int) (string, bool) {
-	word = strings.ToLower(word)
+	query := newMatchQuery(word, maxDistance)
 	bestMatch := ""
-	bestDistance := maxDistance + 1
+	bestDistance := 0.0
 
 	for _, candidate := range dictionary {
-		distance := levenshtein.ComputeDistance(word, strings.ToLower(candidate))
-		if distance < bestDistance && distance <= maxDistance {
+		distance, within := query.distance([]rune(strings.ToLower(candidate)))
+		if within && (bestMatch == "" || distance < bestDistance) {
 			bestDistance = distance
 			bestMatch = candidate
 		}
@@ -102,6 +225,96 @@ func FindClosestMatch(word string, dictionary []string, maxDistance int) (string
 	return bestMatch, bestMatch != ""
 }
 
+const (
+	MatchEditDistance = "edit_distance"
+	MatchPhonetic     = "phonetic"
+)
+
+const (
+	minPhoneticLength     = 5
+	minPhoneticSimilarity = 0.6
+	phoneticWeight        = 0.2
+)
+
+// Match is a dictionary word found for a misspelled one, with its typo distance,
+// the strategy that found it and its ranking score (1 for an exact match).
+type Match struct {
+	Word     string
+	Distance float64
+	Strategy string
+	Score    float64
+}
+
+// FindBestMatch combines the two matching strategies: words within maxDistance
+// edits, and words that sound the same (same Metaphone key) even when further
+// apart ("Filadelfia" → "philadelphia"). Candidates are ranked by their edit
+// similarity, with a bonus when they also sound the same, so "sinsinati" prefers
+// "cincinnati" over a word that is only spelled closer.
+func FindBestMatch(word string, dictionary []string, maxDistance int) (Match, bool) {
+	query := newMatchQuery(word, maxDistance)
+
+	best := Match{}
+	for _, candidate := range dictionary {
+		lower := strings.ToLower(candidate)
+		key := ""
+		if query.key != "" {
+			key = metaphone(lower)
+		}
+		if match, found := query.match(candidate, []rune(lower), key); found && match.Score > best.Score {
+			best = match
+		}
+	}
+	return best, best.Word != ""
+}
+
+// matchQuery is a word being looked up, with what is computed once for it, so
+// the linear scans and the Dictionary index score candidates the same way.
+type matchQuery struct {
+	word      string
+	runes     []rune
+	key       string
+	threshold float64
+}
+
+func newMatchQuery(word string, maxDistance int) matchQuery {
+	query := matchQuery{word: strings.ToLower(word)}
+	query.runes = []rune(query.word)
+	query.threshold = min(float64(maxDistance), maxTypoCost(len(query.runes)))
+	if len(query.runes) >= minPhoneticLength {
+		query.key = metaphone(query.word)
+	}
+	return query
+}
+
+// distance returns the typo distance to a lowercase candidate and whether it is
+// within the threshold.
+func (q matchQuery) distance(candidate []rune) (float64, bool) {
+	distance := typoDistanceRunes(q.runes, candidate)
+	return distance, distance <= q.threshold
+}
+
+// match scores a candidate for FindBestMatch, given its lowercase form and its
+// Metaphone key.
+func (q matchQuery) match(candidate string, lower []rune, key string) (Match, bool) {
+	distance, within := q.distance(lower)
+	similarity := 1 - distance/float64(max(len(q.runes), len(lower)))
+	phonetic := q.key != "" && key == q.key
+
+	strategy := MatchEditDistance
+	if !within {
+		if !phonetic || similarity < minPhoneticSimilarity {
+			return Match{}, false
+		}
+		strategy = MatchPhonetic
+	}
+
+	score := (1 - phoneticWeight) * similarity
+	if phonetic || distance == 0 {
+		score += phoneticWeight
+	}
+	return Match{Word: candidate, Distance: distance, Strategy: strategy, Score: roundScore(score)}, true
+}
+
 func IsValidUSState(state string) bool {
 	state = strings.ToLower(strings.TrimSpace(state))
 
@@ -127,12 +340,7 @@ func NormalizeUSState(state string) (string, bool) {
 		return abbr, true
 	}
 
-	stateNames := make([]string, 0, len(StateAbbreviations))
-	for name := range StateAbbreviations {
-		stateNames = append(stateNames, name)
-	}
-
-	if match, found := FindClosestMatch(state, stateNames, 2); found {
+	if match, found := stateNameDictionary.FindClosest(state, 2); found {
 		return StateAbbreviations[match], true
 	}
 
@@ -37,6 +37,38 @@ func TestFindClosestMatch(t *testing.T) {
 			wantMatch:   "boulevard",
 			wantFound:   true,
 		},
+		{
+			name:        "Adjacent key - 'avenye'",
+			word:        "avenye",
+			dictionary:  CommonStreetTypes,
+			maxDistance: 1,
+			wantMatch:   "avenue",
+			wantFound:   true,
+		},
+		{
+			name:        "Transposed letters - 'avneue'",
+			word:        "avneue",
+			dictionary:  CommonStreetTypes,
+			maxDistance: 1,
+			wantMatch:   "avenue",
+			wantFound:   true,
+		},
+		{
+			name:        "Adjacent key preferred over another substitution",
+			word:        "stteet",
+			dictionary:  []string{"stleet", "street"},
+			maxDistance: 1,
+			wantMatch:   "street",
+			wantFound:   true,
+		},
+		{
+			name:        "Short word limited by its length",
+			word:        "main",
+			dictionary:  []string{"mall"},
+			maxDistance: 2,
+			wantMatch:   "",
+			wantFound:   false,
+		},
 		{
 			name:        "Word without match",
 			word:        "xyz123",
@@ -162,3 +194,217 @@ func TestNormalizeUSState(t *testing.T) {
 		})
 	}
 }
+
+func TestStreetSuffixTable(t *testing.T) {
+	if len(StreetSuffixes) < 190 {
+		t.Fatalf("Expected the full USPS suffix table, got %d suffixes", len(StreetSuffixes))
+	}
+
+	tests := []struct {
+		variant string
+		want    string
+	}{
+		{"st", "street"},
+		{"st.", "street"},
+		{"crk", "creek"},
+		{"xing", "crossing"},
+		{"hts", "heights"},
+		{"mnr", "manor"},
+		{"spgs", "springs"},
+		{"vly", "valley"},
+		{"cntr", "center"},
+		{"tpke", "turnpike"},
+	}
+
+	for _, tt := range tests {
+		if got := StreetAbbreviations[tt.variant]; got != tt.want {
+			t.Errorf("StreetAbbreviations[%q] = %q, want %q", tt.variant, got, tt.want)
+		}
+	}
+
+	for _, variant := range []string{"via", "park", "mall"} {
+		if expansion, exists := StreetAbbreviations[variant]; exists {
+			t.Errorf("StreetAbbreviations[%q] = %q, want no expansion", variant, expansion)
+		}
+	}
+}
+
+func TestNormalizeInputStreetSuffixes(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"45 Mill Crk", "45 Mill creek"},
+		{"9 Deer Xing, Austin, TX", "9 Deer crossing, Austin, TX"},
+		{"200 Shady Vly Rd", "200 Shady valley road"},
+		{"77 Cedar Hollw", "77 Cedar hollow"},
+		{"123 Oak Ct", "123 Oak court"},
+		{"1 Capitol Ave, Hartford, CT", "1 Capitol avenue, Hartford, CT"},
+		{"500 W Jefferson St Louisville KY 40202", "500 west Jefferson street Louisville KY 40202"},
+		{"100 Main St Helena MT 59601", "100 Main street Helena MT 59601"},
+		{"100 Main St Cheyenne WY", "100 Main street Cheyenne WY"},
+		{"100 Main St Hartford CT", "100 Main street Hartford CT"},
+		{"10 Front St", "10 Front street"},
+		{"5 Broadway, New York, NY", "5 Broadway, New York, NY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := validatorService.normalizeInput(tt.input, currentDictionaries(), nil).Normalized; got != tt.want {
+				t.Errorf("normalizeInput(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeInputMultiWordCities(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		input       string
+		want        string
+		wantChanges int
+	}{
+		{"100 Main Street, Los Angelas, CA", "100 Main Street, los angeles, CA", 1},
+		{"200 Canal Street, New Orlens, LA", "200 Canal Street, new orleans, LA", 1},
+		{"300 Alamo Plaza San Antono TX", "300 Alamo Plaza san antonio TX", 1},
+		{"123 Main Stret, San Fransisco, CA", "123 Main street, san francisco, CA", 2},
+		{"5 Broadway, New York, NY", "5 Broadway, New York, NY", 0},
+		{"1 Ocean Blvd Apt 4, Long Beech, CA", "1 Ocean boulevard APT 4, long beach, CA", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
+
+			if result.Normalized != tt.want {
+				t.Errorf("Normalized = %q, want %q", result.Normalized, tt.want)
+			}
+			if len(result.Corrections) != tt.wantChanges {
+				t.Errorf("Changes = %v, want %d changes", result.Corrections, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestFindBestMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		word         string
+		dictionary   []string
+		maxDistance  int
+		wantMatch    string
+		wantStrategy string
+		wantFound    bool
+	}{
+		{
+			name:         "Typo within the edit distance",
+			word:         "stret",
+			dictionary:   CommonStreetTypes,
+			maxDistance:  1,
+			wantMatch:    "street",
+			wantStrategy: MatchEditDistance,
+			wantFound:    true,
+		},
+		{
+			name:         "Phonetic misspelling - 'filadelfia'",
+			word:         "filadelfia",
+			dictionary:   []string{"philadelphia", "pittsburgh", "allentown"},
+			maxDistance:  2,
+			wantMatch:    "philadelphia",
+			wantStrategy: MatchPhonetic,
+			wantFound:    true,
+		},
+		{
+			name:         "Phonetic misspelling - 'sinsinati'",
+			word:         "sinsinati",
+			dictionary:   []string{"cleveland", "cincinnati", "columbus"},
+			maxDistance:  2,
+			wantMatch:    "cincinnati",
+			wantStrategy: MatchPhonetic,
+			wantFound:    true,
+		},
+		{
+			name:         "Same sound ranks above a closer spelling",
+			word:         "foton",
+			dictionary:   []string{"boton", "photon"},
+			maxDistance:  2,
+			wantMatch:    "photon",
+			wantStrategy: MatchPhonetic,
+			wantFound:    true,
+		},
+		{
+			name:        "Same sound but too different",
+			word:        "fenix",
+			dictionary:  []string{"phoenix"},
+			maxDistance: 2,
+			wantFound:   false,
+		},
+		{
+			name:        "Short words have no phonetic match",
+			word:        "nite",
+			dictionary:  []string{"knight"},
+			maxDistance: 1,
+			wantFound:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := FindBestMatch(tt.word, tt.dictionary, tt.maxDistance)
+
+			if found != tt.wantFound {
+				t.Fatalf("FindBestMatch() found = %v, want %v (match: %+v)", found, tt.wantFound, got)
+			}
+			if got.Word != tt.wantMatch || got.Strategy != tt.wantStrategy {
+				t.Errorf("FindBestMatch() = %+v, want %v by %v", got, tt.wantMatch, tt.wantStrategy)
+			}
+		})
+	}
+}
+
+func TestTypoDistance(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want float64
+	}{
+		{"street", "street", 0},
+		{"stteet", "street", 0.5},
+		{"avenye", "avenue", 0.5},
+		{"avneue", "avenue", 0.5},
+		{"stret", "street", 1},
+		{"stxeet", "street", 1},
+		{"bolevard", "boulevard", 1},
+		{"main", "mall", 2},
+	}
+
+	for _, tt := range tests {
+		if got := typoDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("typoDistance(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMetaphone(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"philadelphia", "FLTLF"},
+		{"filadelfia", "FLTLF"},
+		{"cincinnati", "SNSNT"},
+		{"albuquerque", "ALBKRK"},
+		{"knight", "NT"},
+		{"thompson", "0MPSN"},
+		{"los angeles", "LS ANJLS"},
+	}
+
+	for _, tt := range tests {
+		if got := metaphone(tt.word); got != tt.want {
+			t.Errorf("metaphone(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
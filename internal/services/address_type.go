@@ -0,0 +1,162 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const (
+	AddressTypeStreet          = "street"
+	AddressTypePOBox           = "po_box"
+	AddressTypeRuralRoute      = "rural_route"
+	AddressTypeHighwayContract = "highway_contract"
+	AddressTypeMilitary        = "military"
+)
+
+const boxNumberPattern = `#?\s*([a-z0-9-]*\d[a-z0-9-]*)\b`
+
+var (
+	poBoxPattern = regexp.MustCompile(`(?i)^\s*(?:post\s+office\s+box|p\.?\s*o\.?\s*box|p\.?\s*o\.?\s*b\.?|box)\s*` + boxNumberPattern)
+
+	ruralRoutePattern = regexp.MustCompile(`(?i)^\s*(?:rural\s+route|rural\s+rte|r\.?\s*r\.?|r\.?\s*f\.?\s*d\.?)\s*#?\s*(\d+)\s*,?\s*(?:box|bx)\s*` + boxNumberPattern)
+
+	highwayContractPattern = regexp.MustCompile(`(?i)^\s*(?:highway\s+contract(?:\s+route)?|star\s+route|h\.?\s*c\.?)\s*#?\s*(\d+)\s*,?\s*(?:box|bx)\s*` + boxNumberPattern)
+
+	militaryUnitPattern = regexp.MustCompile(`(?i)^\s*(psc|cmr|unit)\s*#?\s*(\d+)\s*,?\s*(?:box|bx)\s*` + boxNumberPattern)
+
+	militaryPostOfficePattern = regexp.MustCompile(`(?i)\b(apo|fpo|dpo)\b[\s,]*(?:\b([a-z]{2})\b)?[\s,]*(\d{5}(?:-\d{4})?)?\s*$`)
+)
+
+// militaryZIPPrefixes are the 3-digit ZIP prefixes USPS assigns to each
+// military "state": AA (Americas), AE (Europe, Middle East, Africa, Canada)
+// and AP (Pacific).
+var militaryZIPPrefixes = map[string][]string{
+	"AA": {"340"},
+	"AE": {"090", "091", "092", "093", "094", "095", "096", "097", "098"},
+	"AP": {"962", "963", "964", "965", "966"},
+}
+
+// deliveryLine is a PO box, rural route or highway contract line standardized
+// to USPS form, e.g. "P.O.B. 45" becomes "PO BOX 45".
+type deliveryLine struct {
+	addressType  string
+	standardized string
+	original     string
+	rest         string
+}
+
+func recognizeDeliveryLine(input string) *deliveryLine {
+	patterns := []struct {
+		addressType string
+		pattern     *regexp.Regexp
+		format      func(match []string) string
+	}{
+		{AddressTypeRuralRoute, ruralRoutePattern, func(match []string) string {
+			return fmt.Sprintf("RR %s BOX %s", match[1], strings.ToUpper(match[2]))
+		}},
+		{AddressTypeHighwayContract, highwayContractPattern, func(match []string) string {
+			return fmt.Sprintf("HC %s BOX %s", match[1], strings.ToUpper(match[2]))
+		}},
+		{AddressTypePOBox, poBoxPattern, func(match []string) string {
+			return fmt.Sprintf("PO BOX %s", strings.ToUpper(match[1]))
+		}},
+	}
+
+	for _, p := range patterns {
+		match := p.pattern.FindStringSubmatch(input)
+		if match == nil {
+			continue
+		}
+
+		return &deliveryLine{
+			addressType:  p.addressType,
+			standardized: p.format(match),
+			original:     strings.TrimSpace(match[0]),
+			rest:         strings.TrimLeft(input[len(match[0]):], " ,"),
+		}
+	}
+	return nil
+}
+
+type militaryAddress struct {
+	line       string
+	postOffice string
+	state      string
+	postalCode string
+}
+
+// recognizeMilitaryAddress matches overseas military mail, which always ends in
+// APO, FPO or DPO followed by the military state and ZIP code. Another 2-letter
+// word after it is only taken as a (wrong) state when a ZIP code follows, so a
+// street such as "12 Dpo Ln" is not military mail.
+func recognizeMilitaryAddress(input string) (*militaryAddress, bool) {
+	match := militaryPostOfficePattern.FindStringSubmatchIndex(input)
+	if match == nil {
+		return nil, false
+	}
+
+	address := &militaryAddress{
+		postOffice: strings.ToUpper(input[match[2]:match[3]]),
+	}
+	if match[4] >= 0 {
+		address.state = strings.ToUpper(input[match[4]:match[5]])
+	}
+	if match[6] >= 0 {
+		address.postalCode = input[match[6]:match[7]]
+	}
+	if _, military := militaryZIPPrefixes[address.state]; address.state != "" && !military && address.postalCode == "" {
+		return nil, false
+	}
+
+	line := strings.Trim(input[:match[0]], " ,")
+	if unit := militaryUnitPattern.FindStringSubmatch(line); unit != nil && len(unit[0]) == len(line) {
+		line = fmt.Sprintf("%s %s BOX %s", strings.ToUpper(unit[1]), unit[2], strings.ToUpper(unit[3]))
+	} else {
+		line = strings.ToUpper(strings.Join(strings.Fields(line), " "))
+	}
+	address.line = line
+
+	return address, true
+}
+
+func (m *militaryAddress) validate() error {
+	prefixes, exists := militaryZIPPrefixes[m.state]
+	if !exists {
+		if m.state == "" {
+			return fmt.Errorf("military address is missing the state code (AA, AE or AP)")
+		}
+		return fmt.Errorf("invalid military state code %q: must be AA, AE or AP", m.state)
+	}
+
+	if m.postalCode == "" {
+		return nil
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(m.postalCode, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("ZIP code %s does not belong to military state %s", m.postalCode, m.state)
+}
+
+func (m *militaryAddress) addressData() *models.AddressData {
+	formatted := fmt.Sprintf("%s %s %s", m.postOffice, m.state, m.postalCode)
+	if m.line != "" {
+		formatted = m.line + ", " + formatted
+	}
+	postalCode, plus4 := splitPostalCode(m.postalCode)
+
+	return &models.AddressData{
+		Street:          m.line,
+		City:            m.postOffice,
+		State:           m.state,
+		PostalCode:      postalCode,
+		PostalCodePlus4: plus4,
+		Country:         "United States",
+		Formatted:       strings.TrimSpace(formatted),
+		Precision:       PrecisionPostcode,
+	}
+}
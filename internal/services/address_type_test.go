@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func TestNormalizeDeliveryLines(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		input           string
+		wantNormalized  string
+		wantAddressType string
+	}{
+		{"PO Box 123, Springfield, IL 62701", "PO BOX 123, Springfield, IL 62701", AddressTypePOBox},
+		{"P.O.B. 45, Austin, TX", "PO BOX 45, Austin, TX", AddressTypePOBox},
+		{"post office box 9 Boston MA", "PO BOX 9, Boston MA", AddressTypePOBox},
+		{"RR 2 Box 10, Springfield, IL", "RR 2 BOX 10, Springfield, IL", AddressTypeRuralRoute},
+		{"Rural Route 3 Bx 7", "RR 3 BOX 7", AddressTypeRuralRoute},
+		{"HC 1 Box 5, Marfa, TX", "HC 1 BOX 5, Marfa, TX", AddressTypeHighwayContract},
+		{"123 Box Elder Rd", "123 Box Elder road", AddressTypeStreet},
+		{"10 Boxwood Ln", "10 Boxwood lane", AddressTypeStreet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
+
+			if result.Normalized != tt.wantNormalized {
+				t.Errorf("Normalized = %q, want %q", result.Normalized, tt.wantNormalized)
+			}
+			if result.AddressType != tt.wantAddressType {
+				t.Errorf("AddressType = %q, want %q", result.AddressType, tt.wantAddressType)
+			}
+		})
+	}
+}
+
+func TestRecognizeMilitaryAddress(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantFound bool
+		wantState string
+	}{
+		{"PSC 1234 Box 5678, APO AE 09021", true, "AE"},
+		{"Unit 45013 Box 2666, FPO AP", true, "AP"},
+		{"PSC 1234 Box 5678, APO NY 09021", true, "NY"},
+		{"PSC 1234 Box 5678, APO 09021", true, ""},
+		{"12 Dpo Ln", false, ""},
+		{"40 Apo Ct", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			military, found := recognizeMilitaryAddress(tt.input)
+			if found != tt.wantFound {
+				t.Fatalf("recognizeMilitaryAddress(%q) found = %v, want %v", tt.input, found, tt.wantFound)
+			}
+			if found && military.state != tt.wantState {
+				t.Errorf("state = %q, want %q", military.state, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestValidateMilitaryAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStatus string
+		wantStreet string
+		wantState  string
+	}{
+		{
+			name:       "PSC box in Europe",
+			input:      "PSC 1234 Box 5678, APO AE 09021",
+			wantStatus: "success",
+			wantStreet: "PSC 1234 BOX 5678",
+			wantState:  "AE",
+		},
+		{
+			name:       "Unit in the Pacific",
+			input:      "unit 45013 box 2666, fpo ap 96326-6113",
+			wantStatus: "success",
+			wantStreet: "UNIT 45013 BOX 2666",
+			wantState:  "AP",
+		},
+		{
+			name:       "Civilian state code",
+			input:      "PSC 1234 Box 5678, APO NY 09021",
+			wantStatus: "error",
+		},
+		{
+			name:       "ZIP outside the military state",
+			input:      "CMR 480 Box 12, APO AP 09021",
+			wantStatus: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeProvider("fake", "Austin")
+			cache := NewMockCacheService()
+			validatorService := NewValidatorService(NewGeocodingService([]Provider{fake}, cache), cache, DefaultGazetteer())
+
+			result, err := validatorService.ValidateAddress(context.Background(), tt.input, models.ValidationOptions{})
+			if err != nil {
+				t.Fatalf("ValidateAddress returned error: %v", err)
+			}
+
+			if fake.calls != 0 {
+				t.Errorf("Military addresses should not be sent to providers, got %d calls", fake.calls)
+			}
+			if result.AddressType != AddressTypeMilitary {
+				t.Errorf("AddressType = %q, want %q", result.AddressType, AddressTypeMilitary)
+			}
+			if result.Status != tt.wantStatus {
+				t.Fatalf("Status = %q, want %q (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if tt.wantStatus != "success" {
+				return
+			}
+
+			if result.Data.Street != tt.wantStreet || result.Data.State != tt.wantState {
+				t.Errorf("Data = %+v, want street %q and state %q", result.Data, tt.wantStreet, tt.wantState)
+			}
+			if result.Verdict != VerdictPartial {
+				t.Errorf("Verdict = %q, want %q", result.Verdict, VerdictPartial)
+			}
+		})
+	}
+}
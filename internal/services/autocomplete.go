@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const AutocompleteMinQueryLength = 3
+
+// autocompletePrefixLookups bounds the cache reads for shorter prefixes of a
+// query. Every answer is cached under its own query, so while typing the
+// previous keystroke's entry is one character back; a query pasted or typed far
+// past any cached prefix goes to the provider instead of costing one read per
+// character.
+const autocompletePrefixLookups = 3
+
+type AutocompleteService struct {
+	geocodingService *GeocodingService
+	cache            Cache
+	timeout          time.Duration
+}
+
+func NewAutocompleteService(geocodingService *GeocodingService, cache Cache, timeout time.Duration) *AutocompleteService {
+	return &AutocompleteService{
+		geocodingService: geocodingService,
+		cache:            cache,
+		timeout:          timeout,
+	}
+}
+
+// autocompleteEntry is what gets cached per query. Complete means the provider
+// returned fewer suggestions than asked for, so the list holds every match for
+// the query and longer queries can be answered by filtering it. When filtering
+// leaves nothing (the provider matched on something other than the prefix), the
+// provider is asked again instead of caching an empty answer.
+type autocompleteEntry struct {
+	Suggestions []models.Suggestion `json:"suggestions"`
+	Complete    bool                `json:"complete"`
+}
+
+func (s *AutocompleteService) Autocomplete(ctx context.Context, query string, limit int) (*models.AutocompleteResponse, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	runes := []rune(strings.ToLower(query))
+
+	if len(runes) < AutocompleteMinQueryLength {
+		return &models.AutocompleteResponse{
+			Status:      "success",
+			Suggestions: []models.Suggestion{},
+		}, nil
+	}
+
+	if entry, found := GetAs[autocompleteEntry](s.cache, s.cacheKey(string(runes), limit)); found {
+		return &models.AutocompleteResponse{
+			Status:      "success",
+			Suggestions: entry.Suggestions,
+		}, nil
+	}
+
+	for n := len(runes) - 1; n >= max(AutocompleteMinQueryLength, len(runes)-autocompletePrefixLookups); n-- {
+		entry, found := GetAs[autocompleteEntry](s.cache, s.cacheKey(string(runes[:n]), limit))
+		if !found || !entry.Complete {
+			continue
+		}
+
+		suggestions := filterSuggestions(entry.Suggestions, string(runes))
+		if len(suggestions) == 0 {
+			break
+		}
+
+		s.cache.Set(s.cacheKey(string(runes), limit), autocompleteEntry{
+			Suggestions: suggestions,
+			Complete:    true,
+		})
+
+		return &models.AutocompleteResponse{
+			Status:      "success",
+			Suggestions: suggestions,
+		}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	suggestions, err := s.geocodingService.Autocomplete(ctx, query, limit)
+	if err != nil {
+		return &models.AutocompleteResponse{
+			Status:      "error",
+			Suggestions: []models.Suggestion{},
+			Error:       fmt.Sprintf("Failed to autocomplete address: %v", err),
+		}, nil
+	}
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	s.cache.Set(s.cacheKey(string(runes), limit), autocompleteEntry{
+		Suggestions: suggestions,
+		Complete:    len(suggestions) < limit,
+	})
+
+	return &models.AutocompleteResponse{
+		Status:      "success",
+		Suggestions: suggestions,
+	}, nil
+}
+
+func filterSuggestions(suggestions []models.Suggestion, query string) []models.Suggestion {
+	filtered := []models.Suggestion{}
+	for _, suggestion := range suggestions {
+		if strings.HasPrefix(strings.ToLower(suggestion.Text), query) {
+			filtered = append(filtered, suggestion)
+		}
+	}
+	return filtered
+}
+
+func (s *AutocompleteService) cacheKey(query string, limit int) string {
+	hash := md5.Sum([]byte(query))
+	return fmt.Sprintf("ac:%d:%s", limit, hex.EncodeToString(hash[:]))
+}
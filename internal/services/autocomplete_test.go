@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func newAutocompleteProvider(name string, texts ...string) *fakeProvider {
+	provider := &fakeProvider{
+		name:         name,
+		capabilities: CapabilityAutocomplete,
+	}
+	for _, text := range texts {
+		provider.suggestions = append(provider.suggestions, models.Suggestion{
+			Text:     text,
+			Address:  &models.AddressData{Formatted: text},
+			Provider: name,
+		})
+	}
+	return provider
+}
+
+func TestAutocompletePrefixCache(t *testing.T) {
+	provider := newAutocompleteProvider("smarty",
+		"123 Main St, San Francisco, CA 94102",
+		"123 Main Ave, Austin, TX 78701",
+	)
+
+	cache := NewMockCacheService()
+	service := NewAutocompleteService(NewGeocodingService([]Provider{provider}, cache), cache, time.Second)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantCount int
+		wantCalls int
+	}{
+		{"Too short query skips providers", "12", 0, 0},
+		{"First query calls provider", "123 Main", 2, 1},
+		{"Same query served from cache", "123  main", 2, 1},
+		{"Longer query filtered from complete prefix", "123 Main St", 1, 1},
+		{"Prefix without local match asks provider", "123 Main Blvd", 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.Autocomplete(context.Background(), tt.query, 5)
+			if err != nil {
+				t.Fatalf("Autocomplete returned error: %v", err)
+			}
+
+			if result.Status != "success" {
+				t.Fatalf("Status = %v, error %v", result.Status, result.Error)
+			}
+
+			if len(result.Suggestions) != tt.wantCount {
+				t.Errorf("Got %d suggestions, want %d", len(result.Suggestions), tt.wantCount)
+			}
+
+			if provider.calls != tt.wantCalls {
+				t.Errorf("Provider called %d times, want %d", provider.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestAutocompleteIncompletePrefixIsNotReused(t *testing.T) {
+	provider := newAutocompleteProvider("smarty",
+		"123 Main St, San Francisco, CA 94102",
+		"123 Main Ave, Austin, TX 78701",
+	)
+
+	cache := NewMockCacheService()
+	service := NewAutocompleteService(NewGeocodingService([]Provider{provider}, cache), cache, time.Second)
+
+	if _, err := service.Autocomplete(context.Background(), "123 Main", 2); err != nil {
+		t.Fatalf("Autocomplete returned error: %v", err)
+	}
+	if _, err := service.Autocomplete(context.Background(), "123 Main St", 2); err != nil {
+		t.Fatalf("Autocomplete returned error: %v", err)
+	}
+
+	if provider.calls != 2 {
+		t.Errorf("Expected provider to be called for each query when results were truncated, got %d calls", provider.calls)
+	}
+}
+
+// countingCache counts the cache reads.
+type countingCache struct {
+	*MockCacheService
+	reads int
+}
+
+func (c *countingCache) GetInto(key string, dest interface{}) bool {
+	c.reads++
+	return c.MockCacheService.GetInto(key, dest)
+}
+
+func TestAutocompletePrefixLookupsAreBounded(t *testing.T) {
+	provider := newAutocompleteProvider("smarty", "123 Main St, San Francisco, CA 94102")
+
+	cache := &countingCache{MockCacheService: NewMockCacheService()}
+	service := NewAutocompleteService(NewGeocodingService([]Provider{provider}, cache), cache, time.Second)
+
+	if _, err := service.Autocomplete(context.Background(), "123", 5); err != nil {
+		t.Fatalf("Autocomplete returned error: %v", err)
+	}
+
+	cache.reads = 0
+	if _, err := service.Autocomplete(context.Background(), "123 Main St, San Francisco", 5); err != nil {
+		t.Fatalf("Autocomplete returned error: %v", err)
+	}
+
+	if want := 1 + autocompletePrefixLookups; cache.reads != want {
+		t.Errorf("Cache read %d times, want %d", cache.reads, want)
+	}
+	if provider.calls != 2 {
+		t.Errorf("Expected a prefix past the lookup window to go to the provider, got %d calls", provider.calls)
+	}
+}
+
+func TestAutocompleteFallback(t *testing.T) {
+	failing := newAutocompleteProvider("geoapify")
+	failing.err = errors.New("timeout")
+	fallback := newAutocompleteProvider("smarty", "123 Main St, San Francisco, CA 94102")
+
+	cache := NewMockCacheService()
+	service := NewAutocompleteService(NewGeocodingService([]Provider{failing, fallback}, cache), cache, time.Second)
+
+	result, err := service.Autocomplete(context.Background(), "123 Main", 5)
+	if err != nil {
+		t.Fatalf("Autocomplete returned error: %v", err)
+	}
+
+	if len(result.Suggestions) != 1 || result.Suggestions[0].Provider != "smarty" {
+		t.Errorf("Expected suggestion from fallback provider, got %+v", result.Suggestions)
+	}
+
+	onlyFailing := NewAutocompleteService(NewGeocodingService([]Provider{failing}, cache), NewMockCacheService(), time.Second)
+	result, err = onlyFailing.Autocomplete(context.Background(), "456 Oak", 5)
+	if err != nil {
+		t.Fatalf("Autocomplete returned error: %v", err)
+	}
+
+	if result.Status != "error" {
+		t.Errorf("Expected error status when every provider fails, got %v", result.Status)
+	}
+}
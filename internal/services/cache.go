@@ -53,6 +53,21 @@ func (s *CacheService) Get(key string) (interface{}, bool) {
 	return result, true
 }
 
+func (s *CacheService) GetInto(key string, dest interface{}) bool {
+	ctx := context.Background()
+
+	val, err := s.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return false
+	}
+
+	if err := json.Unmarshal(val, dest); err != nil {
+		return false
+	}
+
+	return true
+}
+
 func (s *CacheService) Set(key string, value interface{}) {
 	ctx := context.Background()
 
@@ -2,9 +2,12 @@ package services
 
 import (
 	"context"
+	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/henrique/address-validator/internal/models"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/redis"
 )
@@ -205,3 +208,70 @@ func TestCacheItemCount(t *testing.T) {
 		t.Errorf("Expected 2 items after delete, got %d", cache.ItemCount())
 	}
 }
+
+func TestCacheGetIntoStruct(t *testing.T) {
+	cache, cleanup := setupRedisContainer(t)
+	defer cleanup()
+
+	response := &models.ValidateAddressResponse{
+		Status: "success",
+		Data: &models.AddressData{
+			Street:     "Main Street",
+			Number:     "123",
+			City:       "San Francisco",
+			State:      "CA",
+			PostalCode: "94102",
+			Country:    "United States",
+			Formatted:  "123 Main Street, San Francisco, CA 94102",
+		},
+		Corrections: []models.Correction{
+			{Original: "Stret", Replacement: "street", Kind: CorrectionTypo, TokenIndex: 2, Start: 9, End: 14, EditDistance: 1},
+		},
+	}
+
+	cache.Set("addr:test", response)
+
+	cached, found := GetAs[models.ValidateAddressResponse](cache, "addr:test")
+	if !found {
+		t.Fatal("Expected to find cached response")
+	}
+
+	if !reflect.DeepEqual(cached, response) {
+		t.Errorf("Round-trip mismatch: got %+v, want %+v", cached, response)
+	}
+
+	if _, found := GetAs[models.ValidateAddressResponse](cache, "addr:missing"); found {
+		t.Error("Expected missing key to return not found")
+	}
+}
+
+func TestCacheValidateAddressSkipsProviders(t *testing.T) {
+	cache, cleanup := setupRedisContainer(t)
+	defer cleanup()
+
+	var calls int32
+	server := newGeoapifyTestServer(t, &calls)
+
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
+
+	first, err := validatorService.ValidateAddress(context.Background(), "123 Main Stret, San Francisco, CA", models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	second, err := validatorService.ValidateAddress(context.Background(), "123 Main Stret, San Francisco, CA", models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 provider HTTP call, got %d", got)
+	}
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Cached response differs from original: %+v != %+v", second, first)
+	}
+}
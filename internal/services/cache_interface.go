@@ -2,9 +2,18 @@ package services
 
 type Cache interface {
 	Get(key string) (interface{}, bool)
+	GetInto(key string, dest interface{}) bool
 	Set(key string, value interface{})
 	Delete(key string)
 	Flush()
 	ItemCount() int
 	Close() error
 }
+
+func GetAs[T any](cache Cache, key string) (*T, bool) {
+	var value T
+	if !cache.GetInto(key, &value) {
+		return nil, false
+	}
+	return &value, true
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 )
@@ -23,6 +24,21 @@ func (m *MockCacheService) Get(key string) (interface{}, bool) {
 	return val, ok
 }
 
+func (m *MockCacheService) GetInto(key string, dest interface{}) bool {
+	m.mu.RLock()
+	val, ok := m.data[key]
+	m.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
 func (m *MockCacheService) Set(key string, value interface{}) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"math"
+	"strings"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const (
+	VerdictVerified   = "verified"
+	VerdictPartial    = "partial"
+	VerdictAmbiguous  = "ambiguous"
+	VerdictUnverified = "unverified"
+)
+
+const (
+	verifiedThreshold   = 0.8
+	unverifiedThreshold = 0.4
+	ambiguityMargin     = 0.1
+	correctionPenalty   = 0.05
+	minCorrectionFactor = 0.5
+)
+
+// precisionCeilings caps the confidence of a match by how precisely it was located,
+// so a city-level hit for an unknown street can never be reported as verified.
+var precisionCeilings = map[string]float64{
+	PrecisionRooftop:  1,
+	PrecisionStreet:   0.75,
+	PrecisionPostcode: 0.5,
+	PrecisionCity:     0.4,
+	PrecisionRegion:   0.2,
+}
+
+func capByPrecision(score float64, precision string) float64 {
+	ceiling, exists := precisionCeilings[precision]
+	if !exists {
+		ceiling = unverifiedThreshold
+	}
+	return math.Min(score, ceiling)
+}
+
+func correctionFactor(corrections []models.Correction) float64 {
+	factor := 1.0
+	for _, correction := range corrections {
+		if correction.Kind == CorrectionTypo || correction.Kind == CorrectionCity {
+			factor -= correctionPenalty
+		}
+	}
+	return math.Max(factor, minCorrectionFactor)
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
+
+func determineVerdict(confidence float64, candidates []models.Candidate) string {
+	if len(candidates) == 0 || confidence < unverifiedThreshold {
+		return VerdictUnverified
+	}
+
+	if isAmbiguous(candidates) {
+		return VerdictAmbiguous
+	}
+
+	if confidence >= verifiedThreshold && candidates[0].Address.Precision == PrecisionRooftop {
+		return VerdictVerified
+	}
+
+	return VerdictPartial
+}
+
+func isAmbiguous(candidates []models.Candidate) bool {
+	if len(candidates) < 2 {
+		return false
+	}
+
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.Score < unverifiedThreshold || best.Score-candidate.Score > ambiguityMargin {
+			continue
+		}
+		if !strings.EqualFold(candidate.Address.Formatted, best.Address.Formatted) {
+			return true
+		}
+	}
+	return false
+}
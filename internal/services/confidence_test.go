@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func candidate(formatted, precision string, score float64) models.Candidate {
+	return models.Candidate{
+		Address: &models.AddressData{Formatted: formatted, Precision: precision},
+		Score:   score,
+	}
+}
+
+func TestDetermineVerdict(t *testing.T) {
+	tests := []struct {
+		name       string
+		confidence float64
+		candidates []models.Candidate
+		want       string
+	}{
+		{
+			name:       "Rooftop match with high confidence",
+			confidence: 0.95,
+			candidates: []models.Candidate{candidate("123 Main St", PrecisionRooftop, 0.95)},
+			want:       VerdictVerified,
+		},
+		{
+			name:       "Street level match",
+			confidence: 0.75,
+			candidates: []models.Candidate{candidate("Main St", PrecisionStreet, 0.75)},
+			want:       VerdictPartial,
+		},
+		{
+			name:       "City level match of unknown street",
+			confidence: 0.38,
+			candidates: []models.Candidate{candidate("Springfield", PrecisionCity, 0.4)},
+			want:       VerdictUnverified,
+		},
+		{
+			name:       "Two close candidates",
+			confidence: 0.9,
+			candidates: []models.Candidate{
+				candidate("100 Main St, Springfield, IL", PrecisionRooftop, 0.9),
+				candidate("100 Main St, Springfield, MO", PrecisionRooftop, 0.85),
+			},
+			want: VerdictAmbiguous,
+		},
+		{
+			name:       "Second candidate far behind",
+			confidence: 0.9,
+			candidates: []models.Candidate{
+				candidate("100 Main St, Springfield, IL", PrecisionRooftop, 0.9),
+				candidate("Springfield, MO", PrecisionCity, 0.4),
+			},
+			want: VerdictVerified,
+		},
+		{
+			name:       "No candidates",
+			confidence: 0,
+			want:       VerdictUnverified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := determineVerdict(tt.confidence, tt.candidates); got != tt.want {
+				t.Errorf("determineVerdict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCorrectionFactor(t *testing.T) {
+	tests := []struct {
+		name  string
+		kinds []string
+		want  float64
+	}{
+		{"No corrections", nil, 1},
+		{"Abbreviations are free", []string{CorrectionAbbreviation, CorrectionState}, 1},
+		{"One typo", []string{CorrectionTypo}, 0.95},
+		{"Typo and city", []string{CorrectionTypo, CorrectionCity}, 0.9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corrections := make([]models.Correction, len(tt.kinds))
+			for i, kind := range tt.kinds {
+				corrections[i] = models.Correction{Kind: kind}
+			}
+			if got := roundScore(correctionFactor(corrections)); got != tt.want {
+				t.Errorf("correctionFactor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapByPrecision(t *testing.T) {
+	if got := capByPrecision(1, PrecisionCity); got != 0.4 {
+		t.Errorf("capByPrecision(1, city) = %v, want 0.4", got)
+	}
+	if got := capByPrecision(0.9, PrecisionRooftop); got != 0.9 {
+		t.Errorf("capByPrecision(0.9, rooftop) = %v, want 0.9", got)
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const (
+	WarningCityStateMismatch      = "CITY_STATE_MISMATCH"
+	WarningCityZIPMismatch        = "CITY_ZIP_MISMATCH"
+	WarningCityChangedByProvider  = "CITY_CHANGED_BY_PROVIDER"
+	WarningStateChangedByProvider = "STATE_CHANGED_BY_PROVIDER"
+	WarningZIPChangedByProvider   = "ZIP_CHANGED_BY_PROVIDER"
+
+	// ConfidenceLow marks a warning drawn from incomplete data, such as the
+	// 3-digit ZIP prefixes of the bundled gazetteer.
+	ConfidenceLow = "low"
+)
+
+// checkInputConsistency compares the city, state and ZIP code of the input with
+// each other before geocoding. A city the gazetteer does not know is never
+// reported. One it only knows in another state is reported for certain with a
+// complete gazetteer, and as low confidence otherwise, since the partial one
+// misses most places sharing a name (Pasadena, TX or Salem, MA).
+func checkInputConsistency(gazetteer *Gazetteer, input *models.NormalizedInput) []models.Warning {
+	var warnings []models.Warning
+	if input.PostalCode != "" {
+		warnings = append(warnings, postalCodeWarnings(input.PostalCode, input.State)...)
+	}
+	if input.City == "" || input.State == "" {
+		return warnings
+	}
+
+	entry, found := gazetteer.Lookup(input.City, input.State)
+	if !found {
+		states := []string{}
+		for _, place := range gazetteer.Find(input.City) {
+			if !containsString(states, place.State) {
+				states = append(states, place.State)
+			}
+		}
+		if len(states) > 0 && gazetteer.Complete() {
+			warnings = append(warnings, models.Warning{
+				Code:    WarningCityStateMismatch,
+				Message: fmt.Sprintf("%s is in %s, not %s", input.City, strings.Join(states, ", "), input.State),
+				Field:   "city",
+			})
+		} else if len(states) > 0 {
+			warnings = append(warnings, models.Warning{
+				Code:       WarningCityStateMismatch,
+				Message:    fmt.Sprintf("%s is only known in %s, not %s", input.City, strings.Join(states, ", "), input.State),
+				Field:      "city",
+				Confidence: ConfidenceLow,
+			})
+		}
+		return warnings
+	}
+
+	if input.PostalCode != "" && len(warnings) == 0 && len(entry.ZIPCodes) > 0 && !entry.ServesZIP(input.PostalCode) {
+		if warning, found := cityZIPMismatch(gazetteer, entry, input.PostalCode); found {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// cityZIPMismatch reports a ZIP code the place does not serve. When the place
+// lists its full 5-digit codes the mismatch is certain. When it only lists
+// 3-digit prefixes, which miss most of a large city's codes, it is only reported
+// if another place of the state serves the ZIP code, and as low confidence.
+func cityZIPMismatch(gazetteer *Gazetteer, entry GazetteerEntry, zip string) (models.Warning, bool) {
+	if entry.hasFullZIPCodes() {
+		return models.Warning{
+			Code:    WarningCityZIPMismatch,
+			Message: fmt.Sprintf("ZIP code %s is not a ZIP code of %s, %s", zip, entry.City, entry.State),
+			Field:   "postal_code",
+		}, true
+	}
+
+	cities := []string{}
+	for _, place := range gazetteer.entries {
+		if place.State == entry.State && place.City != entry.City && place.ServesZIP(zip) {
+			cities = append(cities, place.City)
+		}
+	}
+	if len(cities) == 0 {
+		return models.Warning{}, false
+	}
+	return models.Warning{
+		Code:       WarningCityZIPMismatch,
+		Message:    fmt.Sprintf("ZIP code %s looks like a ZIP code of %s, not %s", zip, strings.Join(cities, ", "), entry.City),
+		Field:      "postal_code",
+		Confidence: ConfidenceLow,
+	}, true
+}
+
+// checkProviderConsistency reports the components the provider returned
+// differently from the input, which usually means it matched another address.
+func checkProviderConsistency(gazetteer *Gazetteer, input *models.NormalizedInput, result *models.AddressData) []models.Warning {
+	var warnings []models.Warning
+
+	if input.City != "" && result.City != "" && !sameCity(gazetteer, input.City, result.City, result.State) {
+		warnings = append(warnings, models.Warning{
+			Code:    WarningCityChangedByProvider,
+			Message: fmt.Sprintf("Provider returned %s instead of %s", result.City, input.City),
+			Field:   "city",
+		})
+	}
+
+	if input.State != "" && result.State != "" {
+		state, found := NormalizeUSState(result.State)
+		if !found {
+			state = strings.ToUpper(result.State)
+		}
+		if state != input.State {
+			warnings = append(warnings, models.Warning{
+				Code:    WarningStateChangedByProvider,
+				Message: fmt.Sprintf("Provider returned %s instead of %s", state, input.State),
+				Field:   "state",
+			})
+		}
+	}
+
+	if input.PostalCode != "" && result.PostalCode != "" && result.PostalCode != input.PostalCode {
+		warnings = append(warnings, models.Warning{
+			Code:    WarningZIPChangedByProvider,
+			Message: fmt.Sprintf("Provider returned %s instead of %s", result.PostalCode, input.PostalCode),
+			Field:   "postal_code",
+		})
+	}
+
+	return warnings
+}
+
+// sameCity compares city names through the gazetteer, so an alias or another
+// spelling of the same place ("St Louis" and "St. Louis") is not a change.
+func sameCity(gazetteer *Gazetteer, a, b, state string) bool {
+	canonical := func(city string) string {
+		if entry, found := gazetteer.Lookup(city, state); found {
+			return entry.City
+		}
+		return strings.Join(strings.Fields(city), " ")
+	}
+	return strings.EqualFold(canonical(a), canonical(b))
+}
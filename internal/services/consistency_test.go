@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func TestConsistencyWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		result       models.AddressData
+		wantWarnings []string
+	}{
+		{
+			name:         "Consistent input and result",
+			input:        "100 Congress Ave, Austin, TX 78701",
+			result:       models.AddressData{City: "Austin", State: "TX", PostalCode: "78701"},
+			wantWarnings: nil,
+		},
+		{
+			name:   "City, state and ZIP all disagree",
+			input:  "100 Congress Ave, Austin, CA 10001",
+			result: models.AddressData{City: "Austin", State: "TX", PostalCode: "78701"},
+			wantWarnings: []string{
+				WarningStateZIPMismatch, WarningCityStateMismatch,
+				WarningStateChangedByProvider, WarningZIPChangedByProvider,
+			},
+		},
+		{
+			name:         "ZIP code of another city of the state",
+			input:        "1 Main St, Los Angeles, CA 94102",
+			result:       models.AddressData{City: "Los Angeles", State: "CA", PostalCode: "94102"},
+			wantWarnings: []string{WarningCityZIPMismatch},
+		},
+		{
+			name:         "Provider moved the address to another city",
+			input:        "500 Market St, San Francisco, CA",
+			result:       models.AddressData{City: "Oakland", State: "California"},
+			wantWarnings: []string{WarningCityChangedByProvider},
+		},
+		{
+			name:         "Alias is the same city",
+			input:        "700 Clark Ave, St Louis, MO",
+			result:       models.AddressData{City: "Saint Louis", State: "MO"},
+			wantWarnings: nil,
+		},
+		{
+			name:         "City unknown to the gazetteer",
+			input:        "1 Main St, Smallville, KS 66002",
+			result:       models.AddressData{City: "Smallville", State: "KS", PostalCode: "66002"},
+			wantWarnings: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.result
+			result.Precision = PrecisionRooftop
+			fake := &fakeProvider{
+				name:         "fake",
+				capabilities: CapabilityGeocode,
+				candidates:   []models.Candidate{{Address: &result, Provider: "fake", Score: 0.9}},
+			}
+			cache := NewMockCacheService()
+			validatorService := NewValidatorService(NewGeocodingService([]Provider{fake}, cache), cache, DefaultGazetteer())
+
+			response, err := validatorService.ValidateAddress(context.Background(), tt.input, models.ValidationOptions{})
+			if err != nil {
+				t.Fatalf("ValidateAddress returned error: %v", err)
+			}
+
+			var codes []string
+			for _, warning := range response.Warnings {
+				codes = append(codes, warning.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantWarnings) {
+				t.Errorf("Warnings = %v, want %v", response.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestCityStateMismatchConfidence(t *testing.T) {
+	complete, err := ParseGeoNamesGazetteer(strings.NewReader(
+		"US\t91101\tPasadena\tCalifornia\tCA\n" +
+			"US\t77501\tPasadena\tTexas\tTX\n" +
+			"US\t78701\tAustin\tTexas\tTX\n"))
+	if err != nil {
+		t.Fatalf("ParseGeoNamesGazetteer() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		gazetteer      *Gazetteer
+		city           string
+		state          string
+		wantWarning    bool
+		wantConfidence string
+	}{
+		{"Pasadena TX with the seed list", DefaultGazetteer(), "Pasadena", "TX", true, ConfidenceLow},
+		{"Jackson TN with the seed list", DefaultGazetteer(), "Jackson", "TN", true, ConfidenceLow},
+		{"Springfield OR with the seed list", DefaultGazetteer(), "Springfield", "OR", true, ConfidenceLow},
+		{"Richmond CA with the seed list", DefaultGazetteer(), "Richmond", "CA", true, ConfidenceLow},
+		{"Burlington NC with the seed list", DefaultGazetteer(), "Burlington", "NC", true, ConfidenceLow},
+		{"Albany GA with the seed list", DefaultGazetteer(), "Albany", "GA", true, ConfidenceLow},
+		{"Lakewood NJ with the seed list", DefaultGazetteer(), "Lakewood", "NJ", true, ConfidenceLow},
+		{"Cambridge MD with the seed list", DefaultGazetteer(), "Cambridge", "MD", true, ConfidenceLow},
+		{"Athens OH with the seed list", DefaultGazetteer(), "Athens", "OH", true, ConfidenceLow},
+		{"Salem MA with the seed list", DefaultGazetteer(), "Salem", "MA", true, ConfidenceLow},
+		{"Pasadena TX with the complete gazetteer", complete, "Pasadena", "TX", false, ""},
+		{"Austin CA with the complete gazetteer", complete, "Austin", "CA", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &models.NormalizedInput{City: tt.city, State: tt.state}
+			warnings := checkInputConsistency(tt.gazetteer, input)
+
+			if !tt.wantWarning {
+				if len(warnings) != 0 {
+					t.Errorf("Warnings = %v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || warnings[0].Code != WarningCityStateMismatch || warnings[0].Confidence != tt.wantConfidence {
+				t.Errorf("Warnings = %v, want %s with confidence %q", warnings, WarningCityStateMismatch, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestCityZIPMismatchConfidence(t *testing.T) {
+	full, err := ParseGazetteer(strings.NewReader("Los Angeles\tCA\t90001 90012 90210\t\n"), '\t')
+	if err != nil {
+		t.Fatalf("ParseGazetteer() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		gazetteer      *Gazetteer
+		zip            string
+		wantWarning    bool
+		wantConfidence string
+	}{
+		{"Prefix of another city of the state", DefaultGazetteer(), "94102", true, ConfidenceLow},
+		{"Prefix no other city has", DefaultGazetteer(), "90210", false, ""},
+		{"Prefix of the city", DefaultGazetteer(), "90012", false, ""},
+		{"Code missing from the full list", full, "90401", true, ""},
+		{"Code in the full list", full, "90210", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &models.NormalizedInput{City: "Los Angeles", State: "CA", PostalCode: tt.zip}
+			warnings := checkInputConsistency(tt.gazetteer, input)
+
+			if !tt.wantWarning {
+				if len(warnings) != 0 {
+					t.Errorf("Warnings = %v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || warnings[0].Code != WarningCityZIPMismatch || warnings[0].Confidence != tt.wantConfidence {
+				t.Errorf("Warnings = %v, want %s with confidence %q", warnings, WarningCityZIPMismatch, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestCityBeforeState(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"123 Main street, San Francisco, CA 94102", "San Francisco"},
+		{"123 Main street, San Francisco CA", "San Francisco"},
+		{"123 Main street San Francisco CA", ""},
+		{"123 Main street, New Orleans, Louisiana", "New Orleans"},
+		{"Austin, TX", ""},
+	}
+
+	for _, tt := range tests {
+		words := strings.Fields(tt.input)
+		_, index := detectState(words)
+		if got := cityBeforeState(words, index); got != tt.want {
+			t.Errorf("cityBeforeState(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
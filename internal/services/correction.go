@@ -0,0 +1,169 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const (
+	CorrectionAbbreviation  = "abbreviation"
+	CorrectionDirection     = "direction"
+	CorrectionTypo          = "typo"
+	CorrectionCity          = "city"
+	CorrectionCityAlias     = "city_alias"
+	CorrectionState         = "state"
+	CorrectionSecondaryUnit = "secondary_unit"
+	CorrectionPostalCode    = "postal_code"
+)
+
+// correctionLabels are the suffixes of the /api/v1 text form of each kind;
+// abbreviations and directions had none. Delivery lines use their address type
+// as kind ("po_box" → "po box").
+var correctionLabels = map[string]string{
+	CorrectionTypo:             "typo correction",
+	CorrectionCity:             "city correction",
+	CorrectionCityAlias:        "city alias",
+	CorrectionState:            "state",
+	CorrectionSecondaryUnit:    "secondary unit",
+	CorrectionPostalCode:       "zip code",
+	AddressTypePOBox:           "po box",
+	AddressTypeRuralRoute:      "rural route",
+	AddressTypeHighwayContract: "highway contract",
+}
+
+// tokenPosition is where a word being normalized came from: the index of its
+// first token in the input and its character offsets.
+type tokenPosition struct {
+	index int
+	start int
+	end   int
+}
+
+// correctionRecorder records the corrections made to one input text. It keeps a
+// position for each word, merging them when several words are collapsed into
+// one, so every correction points back at what the user typed.
+type correctionRecorder struct {
+	input       []rune
+	field       string
+	positions   []tokenPosition
+	corrections []models.Correction
+}
+
+func newCorrectionRecorder(input string, field string) *correctionRecorder {
+	recorder := &correctionRecorder{input: []rune(input), field: field}
+
+	start := -1
+	for i, r := range recorder.input {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				recorder.positions = append(recorder.positions, tokenPosition{len(recorder.positions), start, i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		recorder.positions = append(recorder.positions, tokenPosition{len(recorder.positions), start, len(recorder.input)})
+	}
+
+	return recorder
+}
+
+// add records that the words from start to end (exclusive) were replaced. A
+// trailing comma is left out of both sides, as it is kept as it was.
+func (r *correctionRecorder) add(kind string, start, end int, replacement string) {
+	r.addMatch(kind, start, end, replacement, "")
+}
+
+// addMatch records a correction found by FindBestMatch with the strategy that
+// found it.
+func (r *correctionRecorder) addMatch(kind string, start, end int, replacement string, strategy string) {
+	first, last := r.positions[start], r.positions[end-1]
+
+	original := string(r.input[first.start:last.end])
+	trimmed := strings.TrimSuffix(original, ",")
+	replacement = strings.TrimSuffix(replacement, ",")
+	if trimmed == replacement {
+		return
+	}
+
+	r.corrections = append(r.corrections, models.Correction{
+		Original:     trimmed,
+		Replacement:  replacement,
+		Kind:         kind,
+		Strategy:     strategy,
+		Field:        r.field,
+		TokenIndex:   first.index,
+		Start:        first.start,
+		End:          first.start + len([]rune(trimmed)),
+		EditDistance: typoDistance(strings.ToLower(trimmed), strings.ToLower(replacement)),
+	})
+}
+
+// addAll records a replacement of the whole input, such as a structured field.
+func (r *correctionRecorder) addAll(kind string, replacement string) {
+	if len(r.positions) > 0 {
+		r.add(kind, 0, len(r.positions), replacement)
+	}
+}
+
+// merge follows collapseSecondaryUnit and collapsePostalCode, which replace the
+// words from start to end with a single one.
+func (r *correctionRecorder) merge(start, end int) {
+	merged := tokenPosition{
+		index: r.positions[start].index,
+		start: r.positions[start].start,
+		end:   r.positions[end-1].end,
+	}
+
+	positions := append([]tokenPosition{}, r.positions[:start]...)
+	positions = append(positions, merged)
+	r.positions = append(positions, r.positions[end:]...)
+}
+
+// shiftCorrections moves corrections made on a suffix of the input, such as the
+// rest after a PO box line, to their position in the whole input.
+func shiftCorrections(corrections []models.Correction, offset, tokens int) []models.Correction {
+	shifted := make([]models.Correction, len(corrections))
+	for i, correction := range corrections {
+		correction.Start += offset
+		correction.End += offset
+		correction.TokenIndex += tokens
+		shifted[i] = correction
+	}
+	return shifted
+}
+
+// CorrectionText formats a correction as the /api/v1 text form.
+func CorrectionText(correction models.Correction) string {
+	text := correction.Original + " → " + correction.Replacement
+	if label := correctionLabels[correction.Kind]; label != "" {
+		text += " (" + label + ")"
+	}
+	return text
+}
+
+// ResponseV1 converts a response to the /api/v1 form.
+func ResponseV1(response *models.ValidateAddressResponse) *models.ValidateAddressResponseV1 {
+	v1 := &models.ValidateAddressResponseV1{ValidateAddressResponse: *response}
+	for _, correction := range response.Corrections {
+		v1.Corrections = append(v1.Corrections, CorrectionText(correction))
+	}
+	return v1
+}
+
+func BatchResultsV1(results []models.BatchValidateResult) []models.BatchValidateResultV1 {
+	v1 := make([]models.BatchValidateResultV1, len(results))
+	for i, result := range results {
+		v1[i] = models.BatchValidateResultV1{
+			ID:                        result.ID,
+			ValidateAddressResponseV1: *ResponseV1(&result.ValidateAddressResponse),
+		}
+	}
+	return v1
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func TestNormalizeInputCorrections(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		name  string
+		input string
+		want  []models.Correction
+	}{
+		{
+			name:  "Typo and abbreviation",
+			input: "123 Main Stret, Apt 4",
+			want: []models.Correction{
+				{Original: "Apt 4", Replacement: "APT 4", Kind: CorrectionSecondaryUnit, TokenIndex: 3, Start: 16, End: 21, EditDistance: 0},
+				{Original: "Stret", Replacement: "street", Kind: CorrectionTypo, Strategy: MatchEditDistance, TokenIndex: 2, Start: 9, End: 14, EditDistance: 1},
+			},
+		},
+		{
+			name:  "Swapped letters count as half an edit",
+			input: "123 Main Avneue",
+			want: []models.Correction{
+				{Original: "Avneue", Replacement: "avenue", Kind: CorrectionTypo, Strategy: MatchEditDistance, TokenIndex: 2, Start: 9, End: 15, EditDistance: 0.5},
+			},
+		},
+		{
+			name:  "Offsets count characters, not bytes",
+			input: "12 Rúa Nueva Ave",
+			want: []models.Correction{
+				{Original: "Ave", Replacement: "avenue", Kind: CorrectionAbbreviation, TokenIndex: 3, Start: 13, End: 16, EditDistance: 3},
+			},
+		},
+		{
+			name:  "Corrections after a PO box line",
+			input: "P.O. Box 45, Boise, Idaho",
+			want: []models.Correction{
+				{Original: "P.O. Box 45", Replacement: "PO BOX 45", Kind: AddressTypePOBox, TokenIndex: 0, Start: 0, End: 11, EditDistance: 2},
+				{Original: "Idaho", Replacement: "ID", Kind: CorrectionState, TokenIndex: 4, Start: 20, End: 25, EditDistance: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
+			if len(result.Corrections) != len(tt.want) {
+				t.Fatalf("Corrections = %+v, want %+v", result.Corrections, tt.want)
+			}
+			for i, correction := range result.Corrections {
+				if correction != tt.want[i] {
+					t.Errorf("Corrections[%d] = %+v, want %+v", i, correction, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCorrectionText(t *testing.T) {
+	tests := []struct {
+		correction models.Correction
+		want       string
+	}{
+		{models.Correction{Original: "Stret", Replacement: "street", Kind: CorrectionTypo}, "Stret → street (typo correction)"},
+		{models.Correction{Original: "Ave", Replacement: "avenue", Kind: CorrectionAbbreviation}, "Ave → avenue"},
+		{models.Correction{Original: "P.O. Box 45", Replacement: "PO BOX 45", Kind: AddressTypePOBox}, "P.O. Box 45 → PO BOX 45 (po box)"},
+	}
+
+	for _, tt := range tests {
+		if got := CorrectionText(tt.correction); got != tt.want {
+			t.Errorf("CorrectionText(%+v) = %q, want %q", tt.correction, got, tt.want)
+		}
+	}
+}
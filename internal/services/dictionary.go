@@ -0,0 +1,167 @@
+package services
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dictionary is a word list indexed for fuzzy lookup: words are grouped by
+// length and carry their letter counts, which bound the typo distance from below
+// so most words are skipped without computing it, and a map of Metaphone keys
+// finds the ones that sound the same. Lookups return the same words as
+// FindClosestMatch and FindBestMatch over the list, without scanning all of it.
+type Dictionary struct {
+	words    []string
+	lower    [][]rune
+	keys     []string
+	counts   []letterCounts
+	byLength [][]int
+	phonetic map[string][]int
+}
+
+// letterCounts counts the letters of a word, with every other character in the
+// last slot.
+type letterCounts [27]uint8
+
+func countLetters(word []rune) letterCounts {
+	var counts letterCounts
+	for _, r := range word {
+		slot := 26
+		if r >= 'a' && r <= 'z' {
+			slot = int(r - 'a')
+		}
+		if counts[slot] < 255 {
+			counts[slot]++
+		}
+	}
+	return counts
+}
+
+func NewDictionary(words []string) *Dictionary {
+	d := &Dictionary{
+		words:    words,
+		lower:    make([][]rune, len(words)),
+		keys:     make([]string, len(words)),
+		counts:   make([]letterCounts, len(words)),
+		phonetic: make(map[string][]int),
+	}
+
+	for i, word := range words {
+		d.lower[i] = []rune(strings.ToLower(word))
+		d.keys[i] = metaphone(string(d.lower[i]))
+		if d.keys[i] != "" {
+			d.phonetic[d.keys[i]] = append(d.phonetic[d.keys[i]], i)
+		}
+		d.counts[i] = countLetters(d.lower[i])
+
+		length := len(d.lower[i])
+		for len(d.byLength) <= length {
+			d.byLength = append(d.byLength, nil)
+		}
+		d.byLength[length] = append(d.byLength[length], i)
+	}
+	return d
+}
+
+// Len returns the number of words in the dictionary.
+func (d *Dictionary) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.words)
+}
+
+// Words returns the words of the dictionary in the order it was built with.
+func (d *Dictionary) Words() []string {
+	if d == nil {
+		return nil
+	}
+	return d.words
+}
+
+// FindClosest is FindClosestMatch over the dictionary.
+func (d *Dictionary) FindClosest(word string, maxDistance int) (string, bool) {
+	query := newMatchQuery(word, maxDistance)
+	bestMatch := ""
+	bestDistance := 0.0
+
+	for _, i := range d.search(query) {
+		distance, within := query.distance(d.lower[i])
+		if within && (bestMatch == "" || distance < bestDistance) {
+			bestDistance = distance
+			bestMatch = d.words[i]
+		}
+	}
+
+	return bestMatch, bestMatch != ""
+}
+
+// FindBest is FindBestMatch over the dictionary.
+func (d *Dictionary) FindBest(word string, maxDistance int) (Match, bool) {
+	query := newMatchQuery(word, maxDistance)
+
+	candidates := d.search(query)
+	if query.key != "" {
+		candidates = append(candidates, d.phonetic[query.key]...)
+		sort.Ints(candidates)
+	}
+
+	best := Match{}
+	for n, i := range candidates {
+		if n > 0 && candidates[n-1] == i {
+			continue
+		}
+		if match, found := query.match(d.words[i], d.lower[i], d.keys[i]); found && match.Score > best.Score {
+			best = match
+		}
+	}
+	return best, best.Word != ""
+}
+
+// search returns, in dictionary order, the words the query may accept as typos.
+// Only insertions and deletions change the length, at a cost of 1 each, so only
+// the lengths within the threshold are read. In the letter counts, an insertion
+// or deletion changes one count, a substitution (half an edit at least) two and
+// a transposition none, so a word whose counts differ by h from the query's and
+// whose length differs by n is at least n + (h-n)/4 away.
+func (d *Dictionary) search(query matchQuery) []int {
+	if d == nil {
+		return nil
+	}
+
+	counts := countLetters(query.runes)
+	budget := int(4 * query.threshold)
+
+	found := []int{}
+	for length := max(0, len(query.runes)-int(query.threshold)); length <= len(query.runes)+int(query.threshold) && length < len(d.byLength); length++ {
+		difference := length - len(query.runes)
+		if difference < 0 {
+			difference = -difference
+		}
+		maxCountDifference := budget - 3*difference
+
+		for _, i := range d.byLength[length] {
+			if withinCountDifference(&counts, &d.counts[i], maxCountDifference) {
+				found = append(found, i)
+			}
+		}
+	}
+
+	sort.Ints(found)
+	return found
+}
+
+func withinCountDifference(a, b *letterCounts, limit int) bool {
+	total := 0
+	for slot := range a {
+		difference := int(a[slot]) - int(b[slot])
+		if difference < 0 {
+			difference = -difference
+		}
+		total += difference
+		if total > limit {
+			return false
+		}
+	}
+	return true
+}
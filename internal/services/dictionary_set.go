@@ -0,0 +1,282 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// DictionarySet is one version of the word lists normalization reads at run
+// time: street suffixes and their abbreviations, directionals, the cities used
+// when the state is unknown and the stop words. A set is never modified once
+// built; a reload builds a new one and swaps it in, so a lookup always sees a
+// complete set.
+type DictionarySet struct {
+	Version string
+
+	suffixes            []StreetSuffix
+	streetTypes         map[string]bool
+	streetAbbreviations map[string]string
+	streetTypeIndex     *Dictionary
+	directions          map[string]string
+	cities              *cityDictionary
+	stopWords           map[string]bool
+}
+
+// dictionaryFile is the JSON layout of a dictionary file. A section left out
+// keeps the built-in list; a section in the file replaces it.
+type dictionaryFile struct {
+	StreetSuffixes []StreetSuffix    `json:"street_suffixes"`
+	Directions     map[string]string `json:"directions"`
+	Cities         []string          `json:"cities"`
+	StopWords      []string          `json:"stop_words"`
+}
+
+var defaultDictionarySet = mustNewDictionarySet(dictionaryFile{
+	StreetSuffixes: StreetSuffixes,
+	Directions:     DirectionAbbreviations,
+	Cities:         CommonCityNames,
+	StopWords:      StopWords,
+})
+
+var dictionaries atomic.Pointer[DictionarySet]
+
+// currentDictionaries returns the set in use: the last one loaded, or the
+// built-in lists.
+func currentDictionaries() *DictionarySet {
+	if set := dictionaries.Load(); set != nil {
+		return set
+	}
+	return defaultDictionarySet
+}
+
+func mustNewDictionarySet(file dictionaryFile) *DictionarySet {
+	set, err := newDictionarySet(file)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in dictionaries: %v", err))
+	}
+	return set
+}
+
+// newDictionarySet validates the lists and indexes them. The version is a hash
+// of their normalized content, so the same lists always get the same version.
+func newDictionarySet(file dictionaryFile) (*DictionarySet, error) {
+	file, err := normalizeDictionaryFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(content)
+
+	set := &DictionarySet{
+		Version:             hex.EncodeToString(hash[:6]),
+		suffixes:            file.StreetSuffixes,
+		streetTypes:         make(map[string]bool),
+		streetAbbreviations: streetSuffixAbbreviations(file.StreetSuffixes),
+		streetTypeIndex:     NewDictionary(streetSuffixNames(file.StreetSuffixes)),
+		directions:          file.Directions,
+		cities: &cityDictionary{
+			names:     NewDictionary(file.Cities),
+			multiWord: NewDictionary(filterMultiWord(file.Cities)),
+		},
+		stopWords: make(map[string]bool),
+	}
+	for _, suffix := range file.StreetSuffixes {
+		set.streetTypes[suffix.Name] = true
+	}
+	for _, word := range file.StopWords {
+		set.stopWords[word] = true
+	}
+	return set, nil
+}
+
+// normalizeDictionaryFile lowercases the lists and rejects entries normalization
+// could not use: empty words, digits in names, and a spelling claimed by two
+// suffixes.
+func normalizeDictionaryFile(file dictionaryFile) (dictionaryFile, error) {
+	normalized := dictionaryFile{
+		StreetSuffixes: make([]StreetSuffix, len(file.StreetSuffixes)),
+		Directions:     make(map[string]string, len(file.Directions)),
+		Cities:         make([]string, len(file.Cities)),
+		StopWords:      make([]string, len(file.StopWords)),
+	}
+
+	variants := make(map[string]string)
+	for i, suffix := range file.StreetSuffixes {
+		name := normalizeDictionaryWord(suffix.Name)
+		if !isDictionaryName(name) || strings.Contains(name, " ") {
+			return dictionaryFile{}, fmt.Errorf("street_suffixes[%d]: invalid name %q", i, suffix.Name)
+		}
+		abbreviation := strings.ToUpper(strings.TrimSpace(suffix.Abbreviation))
+		if abbreviation == "" {
+			return dictionaryFile{}, fmt.Errorf("street_suffixes[%d]: %s has no abbreviation", i, name)
+		}
+
+		normalized.StreetSuffixes[i] = StreetSuffix{Name: name, Abbreviation: abbreviation, Variants: []string{}}
+		for _, variant := range suffix.Variants {
+			variant = normalizeDictionaryWord(variant)
+			if variant == "" || strings.Contains(variant, " ") {
+				return dictionaryFile{}, fmt.Errorf("street_suffixes[%d]: invalid variant %q of %s", i, variant, name)
+			}
+			if other, exists := variants[variant]; exists && other != name {
+				return dictionaryFile{}, fmt.Errorf("street_suffixes[%d]: variant %q of %s is already a variant of %s", i, variant, name, other)
+			}
+			variants[variant] = name
+			normalized.StreetSuffixes[i].Variants = append(normalized.StreetSuffixes[i].Variants, variant)
+		}
+	}
+
+	for abbreviation, direction := range file.Directions {
+		abbreviation, direction = normalizeDictionaryWord(abbreviation), normalizeDictionaryWord(direction)
+		if abbreviation == "" || !isDictionaryName(direction) {
+			return dictionaryFile{}, fmt.Errorf("directions: invalid entry %q: %q", abbreviation, direction)
+		}
+		normalized.Directions[abbreviation] = direction
+	}
+
+	for i, city := range file.Cities {
+		city = normalizeDictionaryWord(city)
+		if !isDictionaryName(city) {
+			return dictionaryFile{}, fmt.Errorf("cities[%d]: invalid city %q", i, city)
+		}
+		normalized.Cities[i] = city
+	}
+
+	for i, word := range file.StopWords {
+		word = normalizeDictionaryWord(word)
+		if !isDictionaryName(word) || strings.Contains(word, " ") {
+			return dictionaryFile{}, fmt.Errorf("stop_words[%d]: invalid word %q", i, word)
+		}
+		normalized.StopWords[i] = word
+	}
+	return normalized, nil
+}
+
+func normalizeDictionaryWord(word string) string {
+	return strings.Join(strings.Fields(strings.ToLower(word)), " ")
+}
+
+// isDictionaryName accepts letters, spaces, periods, apostrophes and hyphens,
+// as in "st. louis" or "coeur d'alene".
+func isDictionaryName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !unicode.IsLetter(c) && !strings.ContainsRune(" .'-", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseDictionaryFile reads a JSON dictionary file, refusing unknown sections so
+// a misspelled one is not silently ignored.
+func ParseDictionaryFile(r io.Reader) (*DictionarySet, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var file dictionaryFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid dictionary file: %w", err)
+	}
+
+	if file.StreetSuffixes == nil {
+		file.StreetSuffixes = StreetSuffixes
+	}
+	if file.Directions == nil {
+		file.Directions = DirectionAbbreviations
+	}
+	if file.Cities == nil {
+		file.Cities = CommonCityNames
+	}
+	if file.StopWords == nil {
+		file.StopWords = StopWords
+	}
+	return newDictionarySet(file)
+}
+
+func LoadDictionaryFile(path string) (*DictionarySet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseDictionaryFile(file)
+}
+
+// DictionaryLoader keeps the dictionaries in use in sync with a file. A file
+// that fails to load is reported and the set in use is kept.
+type DictionaryLoader struct {
+	path    string
+	modTime time.Time
+}
+
+func NewDictionaryLoader(path string) *DictionaryLoader {
+	return &DictionaryLoader{path: path}
+}
+
+// Load reads the file and swaps the set in use. A request takes the set once and
+// passes it through normalization and its cache key, so requests already running
+// finish with the set they started with.
+func (l *DictionaryLoader) Load() (*DictionarySet, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary file: %w", err)
+	}
+	l.modTime = info.ModTime()
+
+	set, err := LoadDictionaryFile(l.path)
+	if err != nil {
+		return nil, err
+	}
+	dictionaries.Store(set)
+	return set, nil
+}
+
+// Watch reloads the file on every signal received on reload (SIGHUP) and when
+// its modification time changes, checked every interval (never when interval is
+// zero), until stop is closed.
+func (l *DictionaryLoader) Watch(interval time.Duration, reload <-chan os.Signal, stop <-chan struct{}) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-reload:
+			l.reload("signal")
+		case <-tick:
+			if info, err := os.Stat(l.path); err == nil && !info.ModTime().Equal(l.modTime) {
+				l.reload("file change")
+			}
+		}
+	}
+}
+
+func (l *DictionaryLoader) reload(reason string) {
+	set, err := l.Load()
+	if err != nil {
+		log.Printf("Dictionary reload (%s) failed, keeping version %s: %v", reason, currentDictionaries().Version, err)
+		return
+	}
+	log.Printf("Dictionaries reloaded (%s), version %s", reason, set.Version)
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func TestParseDictionaryFile(t *testing.T) {
+	data := `{
+		"cities": ["Springfield", "Kansas  City"],
+		"stop_words": ["Lance"]
+	}`
+
+	set, err := ParseDictionaryFile(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseDictionaryFile() error = %v", err)
+	}
+	if set.Version == defaultDictionarySet.Version {
+		t.Errorf("Version = %q, want a version other than the built-in one", set.Version)
+	}
+	if got := set.cities.multiWord.Words(); len(got) != 1 || got[0] != "kansas city" {
+		t.Errorf("multi-word cities = %v, want [kansas city]", got)
+	}
+	if !set.stopWords["lance"] || set.stopWords["main"] {
+		t.Errorf("stop words = %v, want only lance", set.stopWords)
+	}
+	if len(set.suffixes) != len(StreetSuffixes) || set.directions["n"] != "north" {
+		t.Errorf("sections left out of the file do not keep the built-in lists")
+	}
+
+	again, _ := ParseDictionaryFile(strings.NewReader(data))
+	if again.Version != set.Version {
+		t.Errorf("same file got versions %q and %q", set.Version, again.Version)
+	}
+
+	invalid := []struct {
+		name string
+		data string
+	}{
+		{"Malformed JSON", `{"cities": [`},
+		{"Unknown section", `{"city": ["springfield"]}`},
+		{"Digits in a city", `{"cities": ["route 66"]}`},
+		{"Empty stop word", `{"stop_words": [" "]}`},
+		{"Suffix without abbreviation", `{"street_suffixes": [{"name": "street", "variants": ["st"]}]}`},
+		{"Variant of two suffixes", `{"street_suffixes": [
+			{"name": "street", "abbreviation": "ST", "variants": ["st"]},
+			{"name": "saint", "abbreviation": "ST", "variants": ["st"]}
+		]}`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDictionaryFile(strings.NewReader(tt.data)); err == nil {
+				t.Errorf("ParseDictionaryFile(%s) accepted an invalid file", tt.data)
+			}
+		})
+	}
+}
+
+func TestDictionaryLoaderReload(t *testing.T) {
+	t.Cleanup(func() { dictionaries.Store(nil) })
+
+	path := filepath.Join(t.TempDir(), "dictionaries.json")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	write(`{"cities": ["springfield"]}`)
+	loader := NewDictionaryLoader(path)
+	loaded, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if currentDictionaries() != loaded {
+		t.Fatalf("Load() did not swap the dictionaries in use")
+	}
+	if got := validatorService.normalizeInput("1 Main St, Springfeld", currentDictionaries(), nil).Normalized; got != "1 Main street, springfield" {
+		t.Errorf("normalizeInput() = %q, want the city of the loaded file", got)
+	}
+
+	reload := make(chan os.Signal)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		loader.Watch(0, reload, stop)
+		close(done)
+	}()
+
+	write(`{"cities": ["springfield"`)
+	reload <- os.Interrupt
+	write(`{"cities": ["sacramento"]}`)
+	reload <- os.Interrupt
+	close(stop)
+	<-done
+
+	if currentDictionaries() == loaded {
+		t.Fatalf("the dictionaries were not reloaded on the signal")
+	}
+	if got := validatorService.normalizeInput("1 Main St, Sacramnto", currentDictionaries(), nil).Normalized; got != "1 Main street, sacramento" {
+		t.Errorf("normalizeInput() = %q, want the city of the reloaded file", got)
+	}
+}
+
+func TestDictionaryLoaderKeepsSetOnInvalidFile(t *testing.T) {
+	t.Cleanup(func() { dictionaries.Store(nil) })
+
+	path := filepath.Join(t.TempDir(), "dictionaries.json")
+	if err := os.WriteFile(path, []byte(`{"cities": ["route 66"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewDictionaryLoader(path).Load(); err == nil {
+		t.Fatalf("Load() accepted an invalid file")
+	}
+	if currentDictionaries() != defaultDictionarySet {
+		t.Errorf("an invalid file replaced the dictionaries in use")
+	}
+}
+
+// reloadingCache swaps the dictionaries on the first lookup, which happens in
+// the middle of a validation: after normalizing, before parsing and caching.
+type reloadingCache struct {
+	*MockCacheService
+	reload func()
+}
+
+func (c *reloadingCache) GetInto(key string, dest interface{}) bool {
+	if c.reload != nil {
+		c.reload()
+		c.reload = nil
+	}
+	return c.MockCacheService.GetInto(key, dest)
+}
+
+func TestValidateAddressReloadDuringNormalization(t *testing.T) {
+	t.Cleanup(func() { dictionaries.Store(nil) })
+
+	north, err := ParseDictionaryFile(strings.NewReader(`{"directions": {"n": "north"}, "cities": ["springfield"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nord, err := ParseDictionaryFile(strings.NewReader(`{"directions": {"n": "nord"}, "cities": ["shelbyville"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const input = "1 N Main St, Springfeld"
+	const want = "1 north Main street, springfield"
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	// A reload right after the request took its set must not reach any lookup.
+	dictionaries.Store(nord)
+	if got := validatorService.normalizeInput(input, north, nil).Normalized; got != want {
+		t.Errorf("normalizeInput() = %q, want %q from the set of the request", got, want)
+	}
+
+	dictionaries.Store(north)
+	cache := &reloadingCache{MockCacheService: NewMockCacheService(), reload: func() { dictionaries.Store(nord) }}
+	provider := newFakeProvider("fake", "Springfield")
+	validatorService = NewValidatorService(NewGeocodingService([]Provider{provider}, cache), cache, DefaultGazetteer())
+
+	response, err := validatorService.ValidateAddress(context.Background(), input, models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress() error = %v", err)
+	}
+	if provider.query != want {
+		t.Errorf("geocoded %q, want %q", provider.query, want)
+	}
+	if response.Parsed == nil || response.Parsed.PreDirection != "N" {
+		t.Errorf("Parsed = %+v, want the directional of the set of the request", response.Parsed)
+	}
+	if _, cached := cache.Get(validatorService.generateCacheKey(want, cacheNamespace(north, nil))); !cached {
+		t.Errorf("the result was not cached under the version it was normalized with")
+	}
+}
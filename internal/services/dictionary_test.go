@@ -0,0 +1,183 @@
+package services
+
+import (
+	"math/rand"
+	"testing"
+)
+
+var placeSyllables = []string{
+	"ash", "bel", "bright", "cedar", "clear", "dale", "elm", "fair",
+	"ford", "glen", "green", "haven", "hill", "lake", "land", "mont",
+	"new", "north", "oak", "pine", "port", "river", "rock", "san",
+	"spring", "stone", "ton", "vale", "view", "ville", "west", "wood",
+}
+
+// syntheticPlaces builds 32768 place-like names from three syllables each, as a
+// stand-in for a full gazetteer.
+func syntheticPlaces() []string {
+	places := make([]string, 0, len(placeSyllables)*len(placeSyllables)*len(placeSyllables))
+	for _, a := range placeSyllables {
+		for _, b := range placeSyllables {
+			for _, c := range placeSyllables {
+				places = append(places, a+b+c)
+			}
+		}
+	}
+	return places
+}
+
+// gazetteerPlaces builds about 30,000 multi-word place names of varied length,
+// like those of a full gazetteer, from the bundled cities with common prefixes
+// and suffixes ("North Springfield Heights").
+func gazetteerPlaces() []string {
+	prefixes := []string{"", "North ", "South ", "East ", "West ", "New ", "Port ", "Mount ", "Lake ", "Fort "}
+	suffixes := []string{"", " Heights", " Springs", " Park", " City", " Beach", " Hills", " Falls", " Valley", " Junction"}
+
+	places := []string{}
+	for _, entry := range DefaultGazetteer().entries {
+		for _, prefix := range prefixes {
+			for _, suffix := range suffixes {
+				places = append(places, prefix+entry.City+suffix)
+			}
+		}
+	}
+	return places
+}
+
+// misspell makes one or two random typos: a deletion, a doubled letter, a
+// swapped pair or a substitution.
+func misspell(random *rand.Rand, word string) string {
+	letters := []rune(word)
+	for n := random.Intn(2) + 1; n > 0 && len(letters) > 2; n-- {
+		i := random.Intn(len(letters) - 1)
+		switch random.Intn(4) {
+		case 0:
+			letters = append(letters[:i], letters[i+1:]...)
+		case 1:
+			letters = append(letters[:i+1], letters[i:]...)
+		case 2:
+			letters[i], letters[i+1] = letters[i+1], letters[i]
+		default:
+			letters[i] = rune('a' + random.Intn(26))
+		}
+	}
+	return string(letters)
+}
+
+func TestDictionaryMatchesLinearScan(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	lists := map[string][]string{
+		"street types": CommonStreetTypes,
+		"cities":       DefaultGazetteer().cities("CA", currentDictionaries()).names.Words(),
+		"places":       syntheticPlaces()[:1500],
+		"gazetteer":    gazetteerPlaces()[:1000],
+	}
+
+	for name, words := range lists {
+		t.Run(name, func(t *testing.T) {
+			dictionary := NewDictionary(words)
+			if dictionary.Len() != len(words) {
+				t.Fatalf("Len() = %d, want %d", dictionary.Len(), len(words))
+			}
+
+			for n := 0; n < 100; n++ {
+				query := misspell(random, words[random.Intn(len(words))])
+				for maxDistance := 1; maxDistance <= 2; maxDistance++ {
+					wantWord, wantFound := FindClosestMatch(query, words, maxDistance)
+					gotWord, gotFound := dictionary.FindClosest(query, maxDistance)
+					if gotWord != wantWord || gotFound != wantFound {
+						t.Errorf("FindClosest(%q, %d) = %q, %v, want %q, %v", query, maxDistance, gotWord, gotFound, wantWord, wantFound)
+					}
+
+					wantMatch, wantFound := FindBestMatch(query, words, maxDistance)
+					gotMatch, gotFound := dictionary.FindBest(query, maxDistance)
+					if gotMatch != wantMatch || gotFound != wantFound {
+						t.Errorf("FindBest(%q, %d) = %+v, %v, want %+v, %v", query, maxDistance, gotMatch, gotFound, wantMatch, wantFound)
+					}
+				}
+			}
+		})
+	}
+}
+
+func benchmarkQueries(places []string) []string {
+	random := rand.New(rand.NewSource(1))
+	queries := make([]string, 64)
+	for i := range queries {
+		queries[i] = misspell(random, places[random.Intn(len(places))])
+	}
+	return queries
+}
+
+func BenchmarkFindBestMatchLinear(b *testing.B) {
+	places := syntheticPlaces()
+	queries := benchmarkQueries(places)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FindBestMatch(queries[i%len(queries)], places, 2)
+	}
+}
+
+func BenchmarkDictionaryFindBest(b *testing.B) {
+	places := syntheticPlaces()
+	dictionary := NewDictionary(places)
+	queries := benchmarkQueries(places)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dictionary.FindBest(queries[i%len(queries)], 2)
+	}
+}
+
+func BenchmarkFindClosestMatchLinear(b *testing.B) {
+	places := syntheticPlaces()
+	queries := benchmarkQueries(places)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FindClosestMatch(queries[i%len(queries)], places, 1)
+	}
+}
+
+func BenchmarkDictionaryFindClosest(b *testing.B) {
+	places := syntheticPlaces()
+	dictionary := NewDictionary(places)
+	queries := benchmarkQueries(places)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dictionary.FindClosest(queries[i%len(queries)], 1)
+	}
+}
+
+func BenchmarkFindBestMatchLinearGazetteer(b *testing.B) {
+	places := gazetteerPlaces()
+	queries := benchmarkQueries(places)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FindBestMatch(queries[i%len(queries)], places, 2)
+	}
+}
+
+func BenchmarkDictionaryFindBestGazetteer(b *testing.B) {
+	places := gazetteerPlaces()
+	dictionary := NewDictionary(places)
+	queries := benchmarkQueries(places)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dictionary.FindBest(queries[i%len(queries)], 2)
+	}
+}
+
+func BenchmarkNewDictionary(b *testing.B) {
+	places := syntheticPlaces()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewDictionary(places)
+	}
+}
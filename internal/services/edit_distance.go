@@ -0,0 +1,110 @@
+package services
+
+const (
+	adjacentKeyCost   = 0.5
+	transpositionCost = 0.5
+)
+
+var qwertyRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// keyboardNeighbors marks the pairs of letters next to each other on a QWERTY
+// keyboard. Rows are staggered, so the key at column c touches columns c and c+1
+// of the row above and c-1 and c of the row below.
+var keyboardNeighbors = buildKeyboardNeighbors()
+
+func buildKeyboardNeighbors() [26][26]bool {
+	var neighbors [26][26]bool
+	key := func(row, column int) (byte, bool) {
+		if row < 0 || row >= len(qwertyRows) || column < 0 || column >= len(qwertyRows[row]) {
+			return 0, false
+		}
+		return qwertyRows[row][column], true
+	}
+
+	for row, letters := range qwertyRows {
+		for column := 0; column < len(letters); column++ {
+			letter := letters[column]
+			for _, position := range [][2]int{
+				{row, column - 1}, {row, column + 1},
+				{row - 1, column}, {row - 1, column + 1},
+				{row + 1, column - 1}, {row + 1, column},
+			} {
+				if neighbor, exists := key(position[0], position[1]); exists {
+					neighbors[letter-'a'][neighbor-'a'] = true
+				}
+			}
+		}
+	}
+	return neighbors
+}
+
+// typoDistance is a Damerau-Levenshtein distance (optimal string alignment)
+// between two lowercase words, weighted for how people mistype: hitting a key
+// next to the right one ("Avenye") or swapping two letters ("Avneue") costs half
+// an edit, while insertions, deletions and other substitutions cost a full one.
+func typoDistance(a, b string) float64 {
+	return typoDistanceRunes([]rune(a), []rune(b))
+}
+
+func typoDistanceRunes(s, t []rune) float64 {
+	// Only the last three rows are needed, and those of short words fit on the
+	// stack, as this runs for every candidate of a lookup.
+	var buffer [3 * 32]float64
+	width := len(t) + 1
+	var rows []float64
+	if 3*width <= len(buffer) {
+		rows = buffer[:3*width]
+	} else {
+		rows = make([]float64, 3*width)
+	}
+	row := func(i int) []float64 {
+		offset := (i % 3) * width
+		return rows[offset : offset+width]
+	}
+
+	for j := range row(0) {
+		row(0)[j] = float64(j)
+	}
+	for i := 1; i <= len(s); i++ {
+		current, previous, beforePrevious := row(i), row(i+2), row(i+1)
+		current[0] = float64(i)
+		for j := 1; j <= len(t); j++ {
+			current[j] = min(
+				previous[j]+1,
+				current[j-1]+1,
+				previous[j-1]+substitutionCost(s[i-1], t[j-1]),
+			)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && s[i-1] != s[i-2] {
+				current[j] = min(current[j], beforePrevious[j-2]+transpositionCost)
+			}
+		}
+	}
+	return row(len(s))[len(t)]
+}
+
+func substitutionCost(a, b rune) float64 {
+	switch {
+	case a == b:
+		return 0
+	case a >= 'a' && a <= 'z' && b >= 'a' && b <= 'z' && keyboardNeighbors[a-'a'][b-'a']:
+		return adjacentKeyCost
+	default:
+		return 1
+	}
+}
+
+// maxTypoCost is the largest typo distance accepted for a word of the given
+// length. Short words tolerate one edit at most, as two are enough to turn most
+// of them into some other dictionary word; long words allow two.
+func maxTypoCost(length int) float64 {
+	switch {
+	case length <= 3:
+		return adjacentKeyCost
+	case length <= 5:
+		return 1
+	case length <= 8:
+		return 1.5
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GazetteerEntry is a US place with the ZIP codes that serve it. A ZIP code may
+// be a full 5-digit code or a 3-digit prefix covering every code that starts
+// with it.
+type GazetteerEntry struct {
+	City     string
+	State    string
+	ZIPCodes []string
+	Aliases  []string
+}
+
+// Gazetteer indexes places by state so city correction only considers the
+// cities of the state found in the input, and by name and alias for lookups.
+type Gazetteer struct {
+	entries  []GazetteerEntry
+	byState  map[string]*cityDictionary
+	byName   map[string][]int
+	complete bool
+}
+
+// cityDictionary holds lowercase city names, the multi-word ones apart for the
+// span matching, and aliases mapped to the name they stand for. The overlay holds
+// the places of a tenant, looked up before the others.
+type cityDictionary struct {
+	names     *Dictionary
+	multiWord *Dictionary
+	aliases   map[string]string
+	overlay   *cityDictionary
+}
+
+//go:embed data/gazetteer.tsv
+var gazetteerData string
+
+var defaultGazetteer = mustParseGazetteer(gazetteerData)
+
+// DefaultGazetteer returns the gazetteer bundled with the service.
+func DefaultGazetteer() *Gazetteer {
+	return defaultGazetteer
+}
+
+// LoadGazetteerFile reads a gazetteer from a file, in the format chosen by its
+// extension. A .csv or .tsv file has the columns city, state, ZIP codes
+// separated by spaces and aliases separated by semicolons; lines starting with
+// '#' are comments. A .txt file is a GeoNames postal code dump (US.txt), the
+// full list of US ZIP codes with their places.
+func LoadGazetteerFile(path string) (*Gazetteer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gazetteer: %w", err)
+	}
+	defer file.Close()
+
+	var gazetteer *Gazetteer
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt":
+		gazetteer, err = ParseGeoNamesGazetteer(file)
+	case ".csv":
+		gazetteer, err = ParseGazetteer(file, ',')
+	default:
+		gazetteer, err = ParseGazetteer(file, '\t')
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid gazetteer %s: %w", path, err)
+	}
+	return gazetteer, nil
+}
+
+func mustParseGazetteer(data string) *Gazetteer {
+	gazetteer, err := ParseGazetteer(strings.NewReader(data), '\t')
+	if err != nil {
+		panic(fmt.Sprintf("invalid gazetteer: %v", err))
+	}
+	return gazetteer
+}
+
+func ParseGazetteer(r io.Reader, delimiter rune) (*Gazetteer, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	reader.LazyQuotes = true
+
+	entries := []GazetteerEntry{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry := GazetteerEntry{
+			City:     strings.Join(strings.Fields(record[0]), " "),
+			State:    strings.ToUpper(strings.TrimSpace(record[1])),
+			ZIPCodes: strings.Fields(record[2]),
+		}
+		if entry.City == "" {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: missing city", line)
+		}
+		if _, exists := USStates[strings.ToLower(entry.State)]; !exists {
+			return nil, fmt.Errorf("%s: invalid state %q", entry.City, entry.State)
+		}
+		for _, alias := range strings.Split(record[3], ";") {
+			if alias = strings.Join(strings.Fields(alias), " "); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return newGazetteer(entries), nil
+}
+
+// ParseGeoNamesGazetteer reads the tab-separated GeoNames postal code dump:
+// country, ZIP code, place, state name, state code, then county and
+// coordinates, which are not used. The rows of a place are merged into one entry
+// with all its ZIP codes; rows outside the states and DC (territories, military
+// codes) are skipped.
+func ParseGeoNamesGazetteer(r io.Reader) (*Gazetteer, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	entries := []GazetteerEntry{}
+	places := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 5 {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: expected at least 5 columns, got %d", line, len(record))
+		}
+
+		zip := strings.TrimSpace(record[1])
+		city := strings.Join(strings.Fields(record[2]), " ")
+		state := strings.ToUpper(strings.TrimSpace(record[4]))
+		if _, exists := USStates[strings.ToLower(state)]; !exists || city == "" {
+			continue
+		}
+		if !looksLikeZIP(zip) {
+			line, _ := reader.FieldPos(1)
+			return nil, fmt.Errorf("line %d: invalid ZIP code %q", line, zip)
+		}
+
+		key := strings.ToLower(city) + "|" + state
+		index, exists := places[key]
+		if !exists {
+			index = len(entries)
+			places[key] = index
+			entries = append(entries, GazetteerEntry{City: city, State: state})
+		}
+		entries[index].ZIPCodes = append(entries[index].ZIPCodes, zip)
+	}
+
+	gazetteer := newGazetteer(entries)
+	gazetteer.complete = true
+	return gazetteer, nil
+}
+
+func newGazetteer(entries []GazetteerEntry) *Gazetteer {
+	g := &Gazetteer{
+		entries: entries,
+		byState: make(map[string]*cityDictionary),
+		byName:  make(map[string][]int),
+	}
+
+	names := make(map[string][]string)
+	for i, entry := range entries {
+		dictionary, exists := g.byState[entry.State]
+		if !exists {
+			dictionary = &cityDictionary{aliases: make(map[string]string)}
+			g.byState[entry.State] = dictionary
+		}
+
+		name := strings.ToLower(entry.City)
+		names[entry.State] = append(names[entry.State], name)
+		g.byName[name] = append(g.byName[name], i)
+		for _, alias := range entry.Aliases {
+			alias = strings.ToLower(alias)
+			dictionary.aliases[alias] = name
+			if alias != name {
+				g.byName[alias] = append(g.byName[alias], i)
+			}
+		}
+	}
+
+	for state, dictionary := range g.byState {
+		dictionary.names = NewDictionary(names[state])
+		dictionary.multiWord = NewDictionary(filterMultiWord(names[state]))
+	}
+
+	return g
+}
+
+// Len returns the number of places in the gazetteer.
+func (g *Gazetteer) Len() int {
+	return len(g.entries)
+}
+
+// Lookup finds a place by its name or one of its aliases within a state.
+func (g *Gazetteer) Lookup(city, state string) (GazetteerEntry, bool) {
+	state = strings.ToUpper(strings.TrimSpace(state))
+
+	for _, entry := range g.Find(city) {
+		if entry.State == state {
+			return entry, true
+		}
+	}
+	return GazetteerEntry{}, false
+}
+
+// Complete reports whether the gazetteer lists every US place, as the GeoNames
+// dump does, rather than a selection such as the bundled seed list.
+func (g *Gazetteer) Complete() bool {
+	return g.complete
+}
+
+// Find returns the places of every state with the given name or alias.
+func (g *Gazetteer) Find(city string) []GazetteerEntry {
+	city = strings.ToLower(strings.Join(strings.Fields(city), " "))
+
+	entries := []GazetteerEntry{}
+	for _, i := range g.byName[city] {
+		entries = append(entries, g.entries[i])
+	}
+	return entries
+}
+
+// ServesZIP reports whether the ZIP code is one of the place's codes or starts
+// with one of its prefixes.
+func (e GazetteerEntry) ServesZIP(code string) bool {
+	for _, zip := range e.ZIPCodes {
+		if strings.HasPrefix(code, zip) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFullZIPCodes reports whether the place lists 5-digit ZIP codes rather than
+// 3-digit prefixes.
+func (e GazetteerEntry) hasFullZIPCodes() bool {
+	for _, zip := range e.ZIPCodes {
+		if len(zip) < 5 {
+			return false
+		}
+	}
+	return len(e.ZIPCodes) > 0
+}
+
+// cities returns the city dictionary for a state, falling back to the common
+// city names of the dictionaries when the state is unknown or has no places in
+// the gazetteer, so a typo is only corrected towards one of the largest cities.
+func (g *Gazetteer) cities(state string, dictionaries *DictionarySet) *cityDictionary {
+	if g != nil {
+		if dictionary, exists := g.byState[state]; exists {
+			return dictionary
+		}
+	}
+	return dictionaries.cities
+}
+
+func (d *cityDictionary) alias(name string) (string, bool) {
+	if d.overlay != nil {
+		if city, exists := d.overlay.aliases[name]; exists {
+			return city, true
+		}
+	}
+	city, exists := d.aliases[name]
+	return city, exists
+}
+
+func (d *cityDictionary) findName(word string, maxDistance int) (Match, bool) {
+	match, found := d.names.FindBest(word, maxDistance)
+	if d.overlay != nil {
+		return preferOverlay(match, found, d.overlay.names, word, maxDistance)
+	}
+	return match, found
+}
+
+func (d *cityDictionary) findMultiWord(words string, maxDistance int) (Match, bool) {
+	match, found := d.multiWord.FindBest(words, maxDistance)
+	if d.overlay != nil {
+		return preferOverlay(match, found, d.overlay.multiWord, words, maxDistance)
+	}
+	return match, found
+}
+
+// preferOverlay takes the overlay's match unless the other one is closer, so a
+// tenant's own spelling of a place is kept even when a gazetteer city is as close.
+func preferOverlay(match Match, found bool, overlay *Dictionary, word string, maxDistance int) (Match, bool) {
+	if own, ownFound := overlay.FindBest(word, maxDistance); ownFound && (!found || own.Score >= match.Score) {
+		return own, true
+	}
+	return match, found
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGazetteer(t *testing.T) {
+	data := "# city,state,zip_codes,aliases\n" +
+		"Springfield,IL,627,\n" +
+		"Springfield,MO,658 65801,\n" +
+		"Kansas City,MO,641,KC\n" +
+		"Kansas City,KS,661,KC\n"
+
+	gazetteer, err := ParseGazetteer(strings.NewReader(data), ',')
+	if err != nil {
+		t.Fatalf("ParseGazetteer() error = %v", err)
+	}
+	if gazetteer.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", gazetteer.Len())
+	}
+
+	entry, found := gazetteer.Lookup("kc", "ks")
+	if !found || entry.City != "Kansas City" || entry.State != "KS" {
+		t.Errorf("Lookup(kc, ks) = %+v, %v", entry, found)
+	}
+	if _, found := gazetteer.Lookup("Springfield", "TX"); found {
+		t.Errorf("Lookup(Springfield, TX) found a place in another state")
+	}
+
+	if entries := gazetteer.Find(" KC "); len(entries) != 2 {
+		t.Errorf("Find(KC) = %d places, want 2", len(entries))
+	}
+
+	if _, err := ParseGazetteer(strings.NewReader("Toronto\tON\t\t\n"), '\t'); err == nil {
+		t.Errorf("ParseGazetteer() accepted an invalid state")
+	}
+}
+
+func TestParseGeoNamesGazetteer(t *testing.T) {
+	data := "US\t62701\tSpringfield\tIllinois\tIL\tSangamon\t167\t\t\t39.8\t-89.6436\t4\n" +
+		"US\t62702\tSpringfield\tIllinois\tIL\tSangamon\t167\t\t\t39.8202\t-89.6535\t4\n" +
+		"US\t65801\tSpringfield\tMissouri\tMO\tGreene\t077\t\t\t37.2153\t-93.2982\t4\n" +
+		"US\t00601\tAdjuntas\tPuerto Rico\tPR\tAdjuntas\t001\t\t\t18.1627\t-66.7227\t1\n"
+
+	gazetteer, err := ParseGeoNamesGazetteer(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseGeoNamesGazetteer() error = %v", err)
+	}
+	if gazetteer.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", gazetteer.Len())
+	}
+	if !gazetteer.Complete() {
+		t.Errorf("Complete() = false for the GeoNames dump")
+	}
+
+	entry, found := gazetteer.Lookup("springfield", "IL")
+	if !found || strings.Join(entry.ZIPCodes, " ") != "62701 62702" {
+		t.Errorf("Lookup(springfield, IL) = %+v, %v", entry, found)
+	}
+	if !entry.ServesZIP("62702-1234") || entry.ServesZIP("65801") {
+		t.Errorf("ServesZIP() did not match the merged ZIP codes of %+v", entry)
+	}
+	if _, found := gazetteer.Lookup("Adjuntas", "PR"); found {
+		t.Errorf("Lookup(Adjuntas, PR) found a place outside the states")
+	}
+
+	if _, err := ParseGeoNamesGazetteer(strings.NewReader("US\t627\tSpringfield\tIllinois\tIL\n")); err == nil {
+		t.Errorf("ParseGeoNamesGazetteer() accepted an invalid ZIP code")
+	}
+}
+
+func TestDefaultGazetteer(t *testing.T) {
+	gazetteer := DefaultGazetteer()
+	if gazetteer.Complete() {
+		t.Errorf("Complete() = true for the bundled seed list")
+	}
+
+	for state := range USStates {
+		if _, exists := gazetteer.byState[strings.ToUpper(state)]; !exists {
+			t.Errorf("bundled gazetteer has no places in %s", strings.ToUpper(state))
+		}
+	}
+
+	if entry, found := gazetteer.Lookup("Philly", "PA"); !found || entry.City != "Philadelphia" {
+		t.Errorf("Lookup(Philly, PA) = %+v, %v", entry, found)
+	}
+}
+
+func TestNormalizeInputGazetteerCities(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Typo in a city of the state",
+			input: "1200 Truxtun Ave, Bakersfeld, CA 93301",
+			want:  "1200 Truxtun avenue, bakersfield, CA 93301",
+		},
+		{
+			name:  "Multi-word city of the state",
+			input: "200 E Main St, Chapel Hil, NC",
+			want:  "200 east Main street, chapel hill, NC",
+		},
+		{
+			name:  "State detected from the ZIP code",
+			input: "1200 Truxtun Ave, Bakersfeld 93301",
+			want:  "1200 Truxtun avenue, bakersfield 93301",
+		},
+		{
+			name:  "Alias in the city position",
+			input: "1500 Market St, Philly, PA 19102",
+			want:  "1500 Market street, philadelphia, PA 19102",
+		},
+		{
+			name:  "Alias written with a street abbreviation",
+			input: "700 Clark Ave, St Louis, MO",
+			want:  "700 Clark avenue, st. louis, MO",
+		},
+		{
+			name:  "Phonetic misspelling of a city",
+			input: "1 Main St, Filadelfia, PA",
+			want:  "1 Main street, philadelphia, PA",
+		},
+		{
+			name:  "City of another state is not used",
+			input: "1200 Truxtun Ave, Bakersfeld, TX",
+			want:  "1200 Truxtun avenue, Bakersfeld, TX",
+		},
+		{
+			name:  "Unknown state falls back to the common cities",
+			input: "100 Main St, Sacramnto",
+			want:  "100 Main street, sacramento",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
+			if result.Normalized != tt.want {
+				t.Errorf("normalizeInput(%q) = %q, want %q (corrections: %v)", tt.input, result.Normalized, tt.want, result.Corrections)
+			}
+		})
+	}
+}
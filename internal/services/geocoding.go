@@ -2,304 +2,191 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
+	"math"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/henrique/address-validator/internal/models"
 )
 
+// ErrProvidersUnavailable means no provider answered at all, because none is
+// configured or all of them failed, as opposed to none finding the address.
+var ErrProvidersUnavailable = errors.New("no geocoding provider available")
+
 type GeocodingService struct {
-	apiKeyA  string
-	baseURLa string
-	apiKeyB  string
-	baseURLb string
-	cache    Cache
-	client   *http.Client
+	providers []Provider
+	cache     Cache
 }
 
-func NewGeocodingService(apiKeyA, baseURLa, apiKeyB, baseURLb string, cache Cache) *GeocodingService {
+func NewGeocodingService(providers []Provider, cache Cache) *GeocodingService {
 	return &GeocodingService{
-		apiKeyA:  apiKeyA,
-		baseURLa: baseURLa,
-		apiKeyB:  apiKeyB,
-		baseURLb: baseURLb,
-		cache:    cache,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		providers: providers,
+		cache:     cache,
 	}
 }
 
-type GeoapifyResponse struct {
-	Type     string            `json:"type"`
-	Features []GeoapifyFeature `json:"features"`
-	Query    GeoapifyQuery     `json:"query"`
+func (g *GeocodingService) Geocode(ctx context.Context, address string) (*models.GeocodingResponse, error) {
+	result, err := g.firstMatch(CapabilityGeocode, byScore, func(provider Provider) ([]models.Candidate, error) {
+		return provider.Geocode(ctx, address)
+	})
+	if errors.Is(err, ErrProvidersUnavailable) {
+		return result, err
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to geocode address")
+	}
+	return result, nil
 }
 
-type GeoapifyFeature struct {
-	Type       string             `json:"type"`
-	Properties GeoapifyProperties `json:"properties"`
-	Geometry   GeoapifyGeometry   `json:"geometry"`
-	Bbox       []float64          `json:"bbox"`
+// GeocodeStructured sends the components as separate parameters to providers that
+// accept them and falls back to a single-line query for the others.
+func (g *GeocodingService) GeocodeStructured(ctx context.Context, address models.StructuredAddress) (*models.GeocodingResponse, error) {
+	result, err := g.firstMatch(CapabilityGeocode, byScore, func(provider Provider) ([]models.Candidate, error) {
+		if structuredGeocoder, ok := provider.(StructuredGeocoder); ok && provider.Capabilities().Has(CapabilityStructuredGeocode) {
+			return structuredGeocoder.GeocodeStructured(ctx, address)
+		}
+		return provider.Geocode(ctx, formatStructuredAddress(address))
+	})
+	if errors.Is(err, ErrProvidersUnavailable) {
+		return result, err
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to geocode address")
+	}
+	return result, nil
 }
 
-type GeoapifyProperties struct {
-	Country      string  `json:"country"`
-	CountryCode  string  `json:"country_code"`
-	State        string  `json:"state"`
-	StateCode    string  `json:"state_code"`
-	County       string  `json:"county"`
-	City         string  `json:"city"`
-	Postcode     string  `json:"postcode"`
-	Suburb       string  `json:"suburb"`
-	Street       string  `json:"street"`
-	HouseNumber  string  `json:"housenumber"`
-	Formatted    string  `json:"formatted"`
-	AddressLine1 string  `json:"address_line1"`
-	AddressLine2 string  `json:"address_line2"`
-	ResultType   string  `json:"result_type"`
-	Lon          float64 `json:"lon"`
-	Lat          float64 `json:"lat"`
+func (g *GeocodingService) ReverseGeocode(ctx context.Context, latitude, longitude float64) (*models.GeocodingResponse, error) {
+	result, err := g.firstMatch(CapabilityReverseGeocode, byDistance(latitude, longitude), func(provider Provider) ([]models.Candidate, error) {
+		reverseGeocoder, ok := provider.(ReverseGeocoder)
+		if !ok {
+			return nil, fmt.Errorf("reverse geocoding not implemented")
+		}
+		return reverseGeocoder.ReverseGeocode(ctx, latitude, longitude)
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to reverse geocode coordinates")
+	}
+	return result, nil
 }
 
-type GeoapifyGeometry struct {
-	Type        string    `json:"type"`
-	Coordinates []float64 `json:"coordinates"`
+// byScore puts the candidates the provider is most confident about first.
+func byScore(a, b models.Candidate) bool {
+	return a.Score > b.Score
 }
 
-type GeoapifyQuery struct {
-	Text   string                `json:"text"`
-	Parsed GeoapifyParsedAddress `json:"parsed"`
+// byDistance puts the candidates closest to a point first, for reverse geocoding
+// where the nearest address is the answer whatever its match score.
+func byDistance(latitude, longitude float64) func(a, b models.Candidate) bool {
+	return func(a, b models.Candidate) bool {
+		return distanceTo(a.Address, latitude, longitude) < distanceTo(b.Address, latitude, longitude)
+	}
 }
 
-type GeoapifyParsedAddress struct {
-	HouseNumber  string `json:"housenumber"`
-	Street       string `json:"street"`
-	Postcode     string `json:"postcode"`
-	District     string `json:"district"`
-	City         string `json:"city"`
-	Country      string `json:"country"`
-	ExpectedType string `json:"expected_type"`
-}
+// distanceTo returns the great-circle distance in meters from a point to an
+// address, or +Inf when the address has no coordinates.
+func distanceTo(address *models.AddressData, latitude, longitude float64) float64 {
+	if address == nil || address.Latitude == nil || address.Longitude == nil {
+		return math.Inf(1)
+	}
 
-type SmartyResponse struct {
-	Suggestions []SmartySuggestion `json:"suggestions"`
+	const earthRadius = 6371000.0
+	lat1, lat2 := latitude*math.Pi/180, *address.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (*address.Longitude - longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
 }
 
-type SmartySuggestion struct {
-	StreetLine string `json:"street_line"`
-	Secondary  string `json:"secondary"`
-	City       string `json:"city"`
-	State      string `json:"state"`
-	Zipcode    string `json:"zipcode"`
-	Entries    int    `json:"entries"`
-}
+func (g *GeocodingService) firstMatch(capability Capability, less func(a, b models.Candidate) bool, lookup func(Provider) ([]models.Candidate, error)) (*models.GeocodingResponse, error) {
+	answered := false
 
-func (g *GeocodingService) Geocode(ctx context.Context, address string) (*models.GeocodingResponse, error) {
-	if g.apiKeyA != "" && g.baseURLa != "" {
-		result, err := g.geocodeWithGeoapify(ctx, address)
-		if err == nil && result != nil && result.Success {
-			return result, nil
-		}
-		if err != nil {
-			fmt.Printf("Provider A (Geoapify) error: %v, trying fallback...\n", err)
-		} else if result != nil && !result.Success {
-			fmt.Printf("Provider A (Geoapify) returned no results, trying fallback...\n")
-		}
-	}
+	for _, provider := range g.providersWith(capability) {
+		candidates, err := lookup(provider)
+		answered = answered || err == nil
+		if err == nil && len(candidates) > 0 {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return less(candidates[i], candidates[j])
+			})
 
-	if g.apiKeyB != "" && g.baseURLb != "" {
-		result, err := g.geocodeWithSmarty(ctx, address)
-		if err == nil && result != nil && result.Success {
-			return result, nil
+			return &models.GeocodingResponse{
+				Success:     true,
+				AddressData: candidates[0].Address,
+				Candidates:  candidates,
+				Provider:    provider.Name(),
+				Error:       nil,
+			}, nil
 		}
 		if err != nil {
-			fmt.Printf("Provider B (Smarty) error: %v\n", err)
-		} else if result != nil && !result.Success {
-			fmt.Printf("Provider B (Smarty) returned no results\n")
+			fmt.Printf("Provider %s error: %v, trying fallback...\n", provider.Name(), err)
+		} else {
+			fmt.Printf("Provider %s returned no results, trying fallback...\n", provider.Name())
 		}
 	}
 
+	err := fmt.Errorf("all geocoding providers failed")
+	if !answered {
+		err = ErrProvidersUnavailable
+	}
 	return &models.GeocodingResponse{
 		Success:  false,
 		Provider: "none",
-		Error:    fmt.Errorf("all geocoding providers failed"),
-	}, fmt.Errorf("failed to geocode address")
-}
-
-func (g *GeocodingService) geocodeWithGeoapify(ctx context.Context, address string) (*models.GeocodingResponse, error) {
-	params := url.Values{}
-	params.Add("text", address)
-	params.Add("apiKey", g.apiKeyA)
-
-	requestURL := fmt.Sprintf("%s?%s", g.baseURLa, params.Encode())
-
-	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var geoapifyResp GeoapifyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&geoapifyResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	if len(geoapifyResp.Features) == 0 {
-		return &models.GeocodingResponse{
-			Success:  false,
-			Provider: "geoapify",
-			Error:    fmt.Errorf("no results found"),
-		}, nil
-	}
-
-	feature := geoapifyResp.Features[0]
-	props := feature.Properties
-
-	addressData := &models.AddressData{
-		Street:     formatStreetAddress(props.HouseNumber, props.Street),
-		Number:     props.HouseNumber,
-		City:       props.City,
-		State:      props.StateCode,
-		PostalCode: props.Postcode,
-		County:     props.County,
-		Country:    props.Country,
-		Formatted:  props.Formatted,
+		Error:    err,
+	}, err
+}
+
+func formatStructuredAddress(address models.StructuredAddress) string {
+	parts := []string{}
+	for _, part := range []string{
+		address.Line1,
+		address.Line2,
+		address.City,
+		strings.TrimSpace(address.State + " " + address.PostalCode),
+		address.Country,
+	} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
 	}
-
-	return &models.GeocodingResponse{
-		Success:     true,
-		AddressData: addressData,
-		Provider:    "geoapify",
-		Error:       nil,
-	}, nil
+	return strings.Join(parts, ", ")
 }
 
-func (g *GeocodingService) geocodeWithSmarty(ctx context.Context, address string) (*models.GeocodingResponse, error) {
-	params := url.Values{}
-	params.Add("key", g.apiKeyB)
-	params.Add("search", address)
-	params.Add("max_results", "1")
-	params.Add("license", "us-autocomplete-pro-cloud")
-
-	requestURL := fmt.Sprintf("%s?%s", g.baseURLb, params.Encode())
-
-	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Referer", "localhost:3000")
-
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var smartyResp SmartyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&smartyResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	if len(smartyResp.Suggestions) == 0 {
-		return &models.GeocodingResponse{
-			Success:  false,
-			Provider: "smarty",
-			Error:    fmt.Errorf("no results found"),
-		}, nil
-	}
-
-	suggestion := smartyResp.Suggestions[0]
-
-	number, street := parseStreetLine(suggestion.StreetLine)
-
-	addressData := &models.AddressData{
-		Street:     street,
-		Number:     number,
-		City:       suggestion.City,
-		State:      suggestion.State,
-		PostalCode: suggestion.Zipcode,
-		County:     "",
-		Country:    "United States",
-		Formatted:  formatAddress(suggestion),
+func (g *GeocodingService) providersWith(capability Capability) []Provider {
+	providers := make([]Provider, 0, len(g.providers))
+	for _, provider := range g.providers {
+		if provider.Capabilities().Has(capability) {
+			providers = append(providers, provider)
+		}
 	}
-
-	return &models.GeocodingResponse{
-		Success:     true,
-		AddressData: addressData,
-		Provider:    "smarty",
-		Error:       nil,
-	}, nil
+	return providers
 }
 
-func parseStreetLine(streetLine string) (number string, street string) {
-	parts := strings.Fields(streetLine)
-	if len(parts) == 0 {
-		return "", ""
-	}
+func (g *GeocodingService) Autocomplete(ctx context.Context, query string, limit int) ([]models.Suggestion, error) {
+	answered := false
 
-	firstPart := parts[0]
-	if isHouseNumber(firstPart) {
-		number = firstPart
-		if len(parts) > 1 {
-			street = strings.Join(parts[1:], " ")
+	for _, provider := range g.providersWith(CapabilityAutocomplete) {
+		autocompleter, ok := provider.(Autocompleter)
+		if !ok {
+			continue
 		}
-	} else {
-		street = streetLine
-	}
 
-	return number, street
-}
+		suggestions, err := autocompleter.Autocomplete(ctx, query, limit)
+		if err != nil {
+			fmt.Printf("Provider %s autocomplete error: %v, trying fallback...\n", provider.Name(), err)
+			continue
+		}
 
-func isHouseNumber(s string) bool {
-	for _, c := range s {
-		if (c < '0' || c > '9') && c != '-' {
-			return false
+		answered = true
+		if len(suggestions) > 0 {
+			return suggestions, nil
 		}
 	}
-	return len(s) > 0
-}
-
-func formatAddress(s SmartySuggestion) string {
-	parts := []string{s.StreetLine}
 
-	if s.Secondary != "" {
-		parts = append(parts, s.Secondary)
+	if !answered {
+		return nil, fmt.Errorf("all autocomplete providers failed")
 	}
 
-	cityStateZip := fmt.Sprintf("%s, %s %s", s.City, s.State, s.Zipcode)
-	parts = append(parts, cityStateZip)
-
-	return strings.Join(parts, ", ")
-}
-
-func formatStreetAddress(number, street string) string {
-	if number != "" && street != "" {
-		return street
-	}
-	if street != "" {
-		return street
-	}
-	return ""
+	return []models.Suggestion{}, nil
 }
@@ -0,0 +1,452 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+type fakeProvider struct {
+	name         string
+	capabilities Capability
+	candidates   []models.Candidate
+	suggestions  []models.Suggestion
+	err          error
+	calls        int
+	query        string
+}
+
+func (f *fakeProvider) Name() string {
+	return f.name
+}
+
+func (f *fakeProvider) Capabilities() Capability {
+	return f.capabilities
+}
+
+func (f *fakeProvider) Geocode(ctx context.Context, query string) ([]models.Candidate, error) {
+	f.calls++
+	f.query = query
+	return f.candidates, f.err
+}
+
+func (f *fakeProvider) Autocomplete(ctx context.Context, query string, limit int) ([]models.Suggestion, error) {
+	f.calls++
+	if len(f.suggestions) > limit {
+		return f.suggestions[:limit], f.err
+	}
+	return f.suggestions, f.err
+}
+
+func (f *fakeProvider) ReverseGeocode(ctx context.Context, latitude, longitude float64) ([]models.Candidate, error) {
+	f.calls++
+	return f.candidates, f.err
+}
+
+func coordinate(value float64) *float64 {
+	return &value
+}
+
+func newFakeProvider(name string, city string) *fakeProvider {
+	provider := &fakeProvider{
+		name:         name,
+		capabilities: CapabilityGeocode,
+	}
+	if city != "" {
+		provider.candidates = []models.Candidate{
+			{Address: &models.AddressData{City: city}, Provider: name},
+		}
+	}
+	return provider
+}
+
+const geoapifyTestResponse = `{
+	"type": "FeatureCollection",
+	"features": [{
+		"type": "Feature",
+		"properties": {
+			"country": "United States",
+			"country_code": "us",
+			"state": "California",
+			"state_code": "CA",
+			"county": "San Francisco County",
+			"city": "San Francisco",
+			"postcode": "94102",
+			"street": "Main Street",
+			"housenumber": "123",
+			"formatted": "123 Main Street, San Francisco, CA 94102, United States of America",
+			"result_type": "building",
+			"lon": -122.4194,
+			"lat": 37.7749
+		}
+	}]
+}`
+
+const geoapifyAmbiguousTestResponse = `{
+	"type": "FeatureCollection",
+	"features": [{
+		"type": "Feature",
+		"properties": {
+			"country": "United States",
+			"state_code": "IL",
+			"city": "Springfield",
+			"postcode": "62701",
+			"street": "Main Street",
+			"housenumber": "100",
+			"formatted": "100 Main Street, Springfield, IL 62701, United States of America",
+			"result_type": "building",
+			"rank": {"confidence": 0.8}
+		}
+	}, {
+		"type": "Feature",
+		"properties": {
+			"country": "United States",
+			"state_code": "MA",
+			"city": "Springfield",
+			"postcode": "01103",
+			"street": "Main Street",
+			"housenumber": "100",
+			"formatted": "100 Main Street, Springfield, MA 01103, United States of America",
+			"result_type": "building",
+			"rank": {"confidence": 0.9}
+		}
+	}, {
+		"type": "Feature",
+		"properties": {
+			"country": "United States",
+			"state_code": "MO",
+			"city": "Springfield",
+			"formatted": "Springfield, MO, United States of America",
+			"result_type": "city",
+			"rank": {"confidence": 1}
+		}
+	}]
+}`
+
+func newGeoapifyTestServer(t *testing.T, calls *int32) *httptest.Server {
+	return newTestServer(t, calls, geoapifyTestResponse)
+}
+
+func newTestServer(t *testing.T, calls *int32, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGeocodeProviderChain(t *testing.T) {
+	tests := []struct {
+		name         string
+		providers    func() []*fakeProvider
+		wantSuccess  bool
+		wantProvider string
+		wantCalls    []int
+	}{
+		{
+			name: "Primary provider succeeds",
+			providers: func() []*fakeProvider {
+				return []*fakeProvider{newFakeProvider("first", "Austin"), newFakeProvider("second", "Dallas")}
+			},
+			wantSuccess:  true,
+			wantProvider: "first",
+			wantCalls:    []int{1, 0},
+		},
+		{
+			name: "Fallback on provider error",
+			providers: func() []*fakeProvider {
+				first := newFakeProvider("first", "")
+				first.err = errors.New("timeout")
+				return []*fakeProvider{first, newFakeProvider("second", "Dallas")}
+			},
+			wantSuccess:  true,
+			wantProvider: "second",
+			wantCalls:    []int{1, 1},
+		},
+		{
+			name: "Fallback on empty result",
+			providers: func() []*fakeProvider {
+				return []*fakeProvider{newFakeProvider("first", ""), newFakeProvider("second", "Dallas")}
+			},
+			wantSuccess:  true,
+			wantProvider: "second",
+			wantCalls:    []int{1, 1},
+		},
+		{
+			name: "Skip providers without geocode capability",
+			providers: func() []*fakeProvider {
+				first := newFakeProvider("first", "Austin")
+				first.capabilities = 0
+				return []*fakeProvider{first, newFakeProvider("second", "Dallas")}
+			},
+			wantSuccess:  true,
+			wantProvider: "second",
+			wantCalls:    []int{0, 1},
+		},
+		{
+			name: "All providers fail",
+			providers: func() []*fakeProvider {
+				return []*fakeProvider{newFakeProvider("first", ""), newFakeProvider("second", "")}
+			},
+			wantSuccess:  false,
+			wantProvider: "none",
+			wantCalls:    []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakes := tt.providers()
+			providers := make([]Provider, len(fakes))
+			for i, fake := range fakes {
+				providers[i] = fake
+			}
+
+			service := NewGeocodingService(providers, NewMockCacheService())
+			result, err := service.Geocode(context.Background(), "123 Main Street")
+
+			if tt.wantSuccess && err != nil {
+				t.Fatalf("Expected success, got error: %v", err)
+			}
+			if !tt.wantSuccess && err == nil {
+				t.Fatal("Expected error when all providers fail")
+			}
+
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", result.Success, tt.wantSuccess)
+			}
+
+			if result.Provider != tt.wantProvider {
+				t.Errorf("Provider = %v, want %v", result.Provider, tt.wantProvider)
+			}
+
+			for i, fake := range fakes {
+				if fake.calls != tt.wantCalls[i] {
+					t.Errorf("Provider %s called %d times, want %d", fake.name, fake.calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReverseGeocodeNearestFirst(t *testing.T) {
+	provider := &fakeProvider{
+		name:         "fake",
+		capabilities: CapabilityGeocode | CapabilityReverseGeocode,
+		candidates: []models.Candidate{
+			{Address: &models.AddressData{City: "Oakland", Latitude: coordinate(37.8044), Longitude: coordinate(-122.2712)}, Score: 0.95},
+			{Address: &models.AddressData{City: "Daly City", Latitude: coordinate(37.6879), Longitude: coordinate(-122.4702)}, Score: 0.9},
+			{Address: &models.AddressData{City: "San Francisco", Latitude: coordinate(37.7750), Longitude: coordinate(-122.4195)}, Score: 0.6},
+			{Address: &models.AddressData{City: "Unknown"}, Score: 1},
+		},
+	}
+	geocodingService := NewGeocodingService([]Provider{provider}, NewMockCacheService())
+
+	result, err := geocodingService.ReverseGeocode(context.Background(), 37.7749, -122.4194)
+	if err != nil {
+		t.Fatalf("ReverseGeocode returned error: %v", err)
+	}
+
+	var cities []string
+	for _, candidate := range result.Candidates {
+		cities = append(cities, candidate.Address.City)
+	}
+	if want := []string{"San Francisco", "Daly City", "Oakland", "Unknown"}; !reflect.DeepEqual(cities, want) {
+		t.Errorf("Candidates = %v, want %v", cities, want)
+	}
+	if result.AddressData.City != "San Francisco" {
+		t.Errorf("AddressData.City = %v, want San Francisco", result.AddressData.City)
+	}
+}
+
+func TestProviderRegistry(t *testing.T) {
+	registry := DefaultProviderRegistry()
+
+	for _, name := range []string{"geoapify", "smarty", "Geoapify"} {
+		provider, err := registry.Create(name, ProviderOptions{APIKey: "key"})
+		if err != nil {
+			t.Fatalf("Create(%q) returned error: %v", name, err)
+		}
+		if !provider.Capabilities().Has(CapabilityGeocode) {
+			t.Errorf("Provider %s should support geocoding", provider.Name())
+		}
+	}
+
+	if _, err := registry.Create("unknown", ProviderOptions{}); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+
+	registry.Register("fake", func(opts ProviderOptions) Provider {
+		return newFakeProvider("fake", "Austin")
+	})
+
+	provider, err := registry.Create("fake", ProviderOptions{})
+	if err != nil {
+		t.Fatalf("Create(fake) returned error: %v", err)
+	}
+	if provider.Name() != "fake" {
+		t.Errorf("Name() = %v, want fake", provider.Name())
+	}
+}
+
+func TestGeocodeStructured(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(geoapifyTestResponse))
+	}))
+	t.Cleanup(server.Close)
+
+	address := models.StructuredAddress{
+		Line1:      "123 Main Street",
+		Line2:      "Apt 4B",
+		City:       "San Francisco",
+		State:      "CA",
+		PostalCode: "94102",
+	}
+
+	service := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL}),
+	}, NewMockCacheService())
+	if _, err := service.GeocodeStructured(context.Background(), address); err != nil {
+		t.Fatalf("GeocodeStructured returned error: %v", err)
+	}
+
+	want := map[string]string{"housenumber": "123", "street": "Main Street", "city": "San Francisco", "state": "CA", "postcode": "94102"}
+	for name, value := range want {
+		if got := query.Get(name); got != value {
+			t.Errorf("Query parameter %s = %q, want %q", name, got, value)
+		}
+	}
+	if query.Has("text") {
+		t.Error("Structured request should not send free-form text")
+	}
+
+	fake := newFakeProvider("fake", "San Francisco")
+	service = NewGeocodingService([]Provider{fake}, NewMockCacheService())
+	if _, err := service.GeocodeStructured(context.Background(), address); err != nil {
+		t.Fatalf("GeocodeStructured returned error: %v", err)
+	}
+
+	if fake.query != "123 Main Street, Apt 4B, San Francisco, CA 94102" {
+		t.Errorf("Fallback query = %q", fake.query)
+	}
+}
+
+func TestGeoapifyProviderCoordinates(t *testing.T) {
+	var calls int32
+	server := newGeoapifyTestServer(t, &calls)
+
+	provider := NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL})
+	candidates, err := provider.Geocode(context.Background(), "123 Main Street, San Francisco")
+	if err != nil {
+		t.Fatalf("Geocode returned error: %v", err)
+	}
+
+	if len(candidates) != 1 {
+		t.Fatalf("Expected 1 candidate, got %d", len(candidates))
+	}
+
+	data := candidates[0].Address
+	if data.Latitude == nil || data.Longitude == nil || *data.Latitude != 37.7749 || *data.Longitude != -122.4194 {
+		t.Errorf("Coordinates = (%v, %v), want (37.7749, -122.4194)", data.Latitude, data.Longitude)
+	}
+
+	if data.Precision != PrecisionRooftop {
+		t.Errorf("Precision = %v, want %v", data.Precision, PrecisionRooftop)
+	}
+}
+
+func TestAddressDataCoordinatesJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data models.AddressData
+		want string
+	}{
+		{"Point on the equator", models.AddressData{Latitude: coordinate(0), Longitude: coordinate(-78.4678)}, `"latitude":0,"longitude":-78.4678`},
+		{"No coordinates", models.AddressData{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.data)
+			if err != nil {
+				t.Fatalf("json.Marshal returned error: %v", err)
+			}
+			if tt.want == "" && strings.Contains(string(body), "latitude") {
+				t.Errorf("JSON = %s, want no coordinates", body)
+			}
+			if tt.want != "" && !strings.Contains(string(body), tt.want) {
+				t.Errorf("JSON = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeoapifyPrecisionAndBoundingBox(t *testing.T) {
+	tests := []struct {
+		resultType string
+		want       string
+	}{
+		{"building", PrecisionRooftop},
+		{"amenity", PrecisionRooftop},
+		{"street", PrecisionStreet},
+		{"postcode", PrecisionPostcode},
+		{"city", PrecisionCity},
+		{"suburb", PrecisionCity},
+		{"state", PrecisionRegion},
+		{"unknown", ""},
+	}
+
+	for _, tt := range tests {
+		if got := geoapifyPrecision(tt.resultType); got != tt.want {
+			t.Errorf("geoapifyPrecision(%q) = %q, want %q", tt.resultType, got, tt.want)
+		}
+	}
+
+	bbox := geoapifyBoundingBox([]float64{-122.527, 37.7034, -122.3482, 37.8324})
+	if bbox == nil {
+		t.Fatal("Expected bounding box")
+	}
+	if bbox.MinLongitude != -122.527 || bbox.MinLatitude != 37.7034 || bbox.MaxLongitude != -122.3482 || bbox.MaxLatitude != 37.8324 {
+		t.Errorf("Unexpected bounding box: %+v", bbox)
+	}
+
+	if geoapifyBoundingBox(nil) != nil {
+		t.Error("Expected nil bounding box for missing bbox")
+	}
+}
+
+func TestSmartyPrecision(t *testing.T) {
+	tests := []struct {
+		name       string
+		suggestion SmartySuggestion
+		want       string
+	}{
+		{"Single delivery point", SmartySuggestion{StreetLine: "123 Main St", Entries: 0}, PrecisionRooftop},
+		{"Unit of a building", SmartySuggestion{StreetLine: "123 Main St", Secondary: "Apt 4", Entries: 1}, PrecisionRooftop},
+		{"Building with several units", SmartySuggestion{StreetLine: "123 Main St", Secondary: "Apt", Entries: 12}, PrecisionStreet},
+		{"Non-postal house number", SmartySuggestion{StreetLine: "123 Main St", Source: "other"}, PrecisionStreet},
+		{"Street without house number", SmartySuggestion{StreetLine: "Main St"}, PrecisionStreet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := smartyAddressData(tt.suggestion).Precision; got != tt.want {
+				t.Errorf("Precision = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+
+	jobChunkSize = 100
+
+	// A running job renews its lease every jobHeartbeatInterval; a job whose
+	// lease is older than jobLeaseTimeout lost its worker and is marked failed.
+	jobHeartbeatInterval = 30 * time.Second
+	jobLeaseTimeout      = 2 * time.Minute
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+var jobResultColumns = []string{
+	"status", "confidence", "verdict", "street", "number", "secondary", "city", "state",
+	"postal_code", "postal_code_plus4", "county", "country", "formatted", "latitude", "longitude",
+	"precision", "address_type", "corrections", "warnings", "error",
+}
+
+type JobService struct {
+	validatorService *ValidatorService
+	cache            Cache
+	workers          int
+	leaseTimeout     time.Duration
+}
+
+func NewJobService(validatorService *ValidatorService, cache Cache, workers int) *JobService {
+	return &JobService{
+		validatorService: validatorService,
+		cache:            cache,
+		workers:          workers,
+		leaseTimeout:     jobLeaseTimeout,
+	}
+}
+
+type jobInput struct {
+	header        []string
+	rows          [][]string
+	addressColumn int
+	idColumn      int
+}
+
+// CreateJob validates the rows in the background, for the tenant of ctx if any.
+// The job belongs to that tenant: no other token can read it.
+func (s *JobService) CreateJob(ctx context.Context, r io.Reader, opts models.ValidationOptions) (*models.Job, error) {
+	input, err := readJobInput(r)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := generateJobID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	now := time.Now().UTC()
+	job := &models.Job{
+		ID:        id,
+		Tenant:    tenantName(TenantFromContext(ctx)),
+		Status:    JobStatusQueued,
+		Total:     len(input.rows),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.saveJob(job)
+	s.renewLease(job.ID)
+
+	created := *job
+	go s.run(WithTenant(context.Background(), TenantFromContext(ctx)), job, input, opts)
+
+	return &created, nil
+}
+
+// GetJob returns a job of the tenant of ctx. A job of another tenant is not
+// found, so its ID cannot be probed. A queued or running job whose worker
+// stopped renewing its lease is marked failed.
+func (s *JobService) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	job, found := GetAs[models.Job](s.cache, jobKey(id))
+	if !found || job.Tenant != tenantName(TenantFromContext(ctx)) {
+		return nil, ErrJobNotFound
+	}
+
+	if (job.Status == JobStatusQueued || job.Status == JobStatusRunning) && s.leaseExpired(job) {
+		job.Status = JobStatusFailed
+		job.Error = "job stopped: its worker is no longer running"
+		s.saveJob(job)
+	}
+	return job, nil
+}
+
+// WriteResult writes the result CSV of a completed job to w, one stored chunk
+// at a time. Nothing is written when the result is not found.
+func (s *JobService) WriteResult(ctx context.Context, id string, w io.Writer) error {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	chunks := jobResultChunks(job.Total)
+	first, found := GetAs[string](s.cache, jobResultKey(id, 0))
+	if !found {
+		return ErrJobNotFound
+	}
+	if _, err := io.WriteString(w, *first); err != nil {
+		return err
+	}
+
+	for n := 1; n < chunks; n++ {
+		chunk, found := GetAs[string](s.cache, jobResultKey(id, n))
+		if !found {
+			return fmt.Errorf("job result chunk %d not found", n)
+		}
+		if _, err := io.WriteString(w, *chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JobService) run(ctx context.Context, job *models.Job, input *jobInput, opts models.ValidationOptions) {
+	done := make(chan struct{})
+	defer close(done)
+	go s.heartbeat(job.ID, done)
+
+	job.Status = JobStatusRunning
+	s.saveJob(job)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(append(append([]string{}, input.header...), jobResultColumns...))
+
+	for start := 0; start < len(input.rows); start += jobChunkSize {
+		end := start + jobChunkSize
+		if end > len(input.rows) {
+			end = len(input.rows)
+		}
+
+		chunk := input.rows[start:end]
+		items := make([]models.BatchAddressItem, len(chunk))
+		for i, row := range chunk {
+			items[i] = models.BatchAddressItem{
+				ID:      columnValue(row, input.idColumn),
+				Address: columnValue(row, input.addressColumn),
+			}
+		}
+
+		results := s.validatorService.ValidateAddresses(ctx, items, opts, s.workers)
+		for i, result := range results {
+			if result.Status == "success" {
+				job.Succeeded++
+			} else {
+				job.Failed++
+			}
+			writer.Write(append(padRow(chunk[i], len(input.header)), jobResultRow(result)...))
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			job.Status = JobStatusFailed
+			job.Error = fmt.Sprintf("failed to write result: %v", err)
+			s.saveJob(job)
+			return
+		}
+		s.cache.Set(jobResultKey(job.ID, start/jobChunkSize), buf.String())
+		buf.Reset()
+
+		job.Processed = end
+		job.Progress = float64(job.Processed) / float64(job.Total)
+		s.saveJob(job)
+	}
+
+	job.Status = JobStatusCompleted
+	job.Progress = 1
+	job.ResultURL = fmt.Sprintf("/api/v1/jobs/%s/result", job.ID)
+	s.saveJob(job)
+}
+
+func (s *JobService) saveJob(job *models.Job) {
+	job.UpdatedAt = time.Now().UTC()
+	s.cache.Set(jobKey(job.ID), *job)
+}
+
+// heartbeat renews the lease of a job until done is closed, so a job whose
+// process died is detected even while a slow chunk is being validated.
+func (s *JobService) heartbeat(id string, done <-chan struct{}) {
+	ticker := time.NewTicker(jobHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.renewLease(id)
+		}
+	}
+}
+
+func (s *JobService) renewLease(id string) {
+	s.cache.Set(jobLeaseKey(id), time.Now().UTC())
+}
+
+// leaseExpired falls back to the last update of the job when it has no lease.
+func (s *JobService) leaseExpired(job *models.Job) bool {
+	renewed := job.UpdatedAt
+	if lease, found := GetAs[time.Time](s.cache, jobLeaseKey(job.ID)); found {
+		renewed = *lease
+	}
+	return time.Since(renewed) > s.leaseTimeout
+}
+
+// jobResultChunks returns the number of result chunks of a job: one per chunk of
+// input rows, the first one starting with the header.
+func jobResultChunks(total int) int {
+	return max(1, (total+jobChunkSize-1)/jobChunkSize)
+}
+
+func readJobInput(r io.Reader) (*jobInput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	input := &jobInput{
+		header:        header,
+		addressColumn: -1,
+		idColumn:      -1,
+	}
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "address":
+			input.addressColumn = i
+		case "id":
+			input.idColumn = i
+		}
+	}
+	if input.addressColumn == -1 {
+		return nil, fmt.Errorf("CSV header must contain an address column")
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		input.rows = append(input.rows, row)
+	}
+
+	if len(input.rows) == 0 {
+		return nil, fmt.Errorf("CSV file has no rows")
+	}
+
+	return input, nil
+}
+
+func jobResultRow(result models.BatchValidateResult) []string {
+	data := result.Data
+	if data == nil {
+		data = &models.AddressData{}
+	}
+
+	return []string{
+		result.Status,
+		formatScore(result.Confidence),
+		result.Verdict,
+		data.Street,
+		data.Number,
+		data.Secondary,
+		data.City,
+		data.State,
+		data.PostalCode,
+		data.PostalCodePlus4,
+		data.County,
+		data.Country,
+		data.Formatted,
+		formatCoordinate(data.Latitude),
+		formatCoordinate(data.Longitude),
+		data.Precision,
+		result.AddressType,
+		jobCorrections(result.Corrections),
+		jobWarnings(result.Warnings),
+		result.Error,
+	}
+}
+
+func jobCorrections(corrections []models.Correction) string {
+	texts := make([]string, len(corrections))
+	for i, correction := range corrections {
+		texts[i] = CorrectionText(correction)
+	}
+	return strings.Join(texts, "; ")
+}
+
+func jobWarnings(warnings []models.Warning) string {
+	codes := make([]string, len(warnings))
+	for i, warning := range warnings {
+		codes[i] = warning.Code
+	}
+	return strings.Join(codes, "; ")
+}
+
+func formatCoordinate(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func formatScore(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func columnValue(row []string, column int) string {
+	if column < 0 || column >= len(row) {
+		return ""
+	}
+	return row[column]
+}
+
+func padRow(row []string, size int) []string {
+	padded := make([]string, size)
+	copy(padded, row)
+	return padded
+}
+
+func generateJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func jobKey(id string) string {
+	return "job:" + id
+}
+
+func jobResultKey(id string, chunk int) string {
+	return fmt.Sprintf("job:%s:result:%d", id, chunk)
+}
+
+func jobLeaseKey(id string) string {
+	return "job:" + id + ":lease"
+}
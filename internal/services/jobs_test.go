@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func TestJobServiceProcessesCSV(t *testing.T) {
+	var calls int32
+	server := newGeoapifyTestServer(t, &calls)
+
+	cache := NewMockCacheService()
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL}),
+	}, cache)
+	jobService := NewJobService(NewValidatorService(geocodingService, cache, DefaultGazetteer()), cache, 2)
+
+	input := "id,address,source\n" +
+		"1,\"123 Main Stret, San Francisco, CA\",crm\n" +
+		"2,,crm\n" +
+		"3,\"456 Oak Ave, San Francisco, CA\",web\n"
+
+	job, err := jobService.CreateJob(context.Background(), strings.NewReader(input), models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("CreateJob returned error: %v", err)
+	}
+
+	if job.Total != 3 {
+		t.Errorf("Total = %d, want 3", job.Total)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err = jobService.GetJob(context.Background(), job.ID)
+		if err != nil {
+			t.Fatalf("GetJob returned error: %v", err)
+		}
+		if job.Status == JobStatusCompleted || job.Status == JobStatusFailed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job did not complete in time, status %s", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Status != JobStatusCompleted {
+		t.Fatalf("Job status = %s, error %s", job.Status, job.Error)
+	}
+
+	if job.Processed != 3 || job.Succeeded != 2 || job.Failed != 1 {
+		t.Errorf("Unexpected counters: processed=%d succeeded=%d failed=%d", job.Processed, job.Succeeded, job.Failed)
+	}
+
+	var result strings.Builder
+	if err := jobService.WriteResult(context.Background(), job.ID, &result); err != nil {
+		t.Fatalf("WriteResult returned error: %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(result.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Result is not valid CSV: %v", err)
+	}
+
+	if len(rows) != 4 {
+		t.Fatalf("Expected header and 3 rows, got %d rows", len(rows))
+	}
+
+	wantHeader := append([]string{"id", "address", "source"}, jobResultColumns...)
+	if strings.Join(rows[0], ",") != strings.Join(wantHeader, ",") {
+		t.Errorf("Header = %v, want %v", rows[0], wantHeader)
+	}
+
+	if rows[1][0] != "1" || rows[1][3] != "success" || rows[1][9] != "San Francisco" {
+		t.Errorf("Unexpected first row: %v", rows[1])
+	}
+
+	if rows[2][3] != "error" {
+		t.Errorf("Expected error status for empty address, got %v", rows[2])
+	}
+}
+
+func TestJobServiceResultChunks(t *testing.T) {
+	cache := NewMockCacheService()
+	jobService := NewJobService(NewValidatorService(NewGeocodingService(nil, cache), cache, DefaultGazetteer()), cache, 2)
+
+	var input strings.Builder
+	input.WriteString("id,address\n")
+	for i := 1; i <= 2*jobChunkSize+50; i++ {
+		fmt.Fprintf(&input, "%d,\n", i)
+	}
+
+	job, err := jobService.CreateJob(context.Background(), strings.NewReader(input.String()), models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("CreateJob returned error: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != JobStatusCompleted && job.Status != JobStatusFailed {
+		if time.Now().After(deadline) {
+			t.Fatalf("Job did not complete in time, status %s", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = jobService.GetJob(context.Background(), job.ID); err != nil {
+			t.Fatalf("GetJob returned error: %v", err)
+		}
+	}
+
+	if job.Status != JobStatusCompleted {
+		t.Fatalf("Job status = %s, error %s", job.Status, job.Error)
+	}
+
+	for n := 0; n < 3; n++ {
+		if _, found := cache.Get(jobResultKey(job.ID, n)); !found {
+			t.Errorf("result chunk %d was not stored", n)
+		}
+	}
+
+	var result strings.Builder
+	if err := jobService.WriteResult(context.Background(), job.ID, &result); err != nil {
+		t.Fatalf("WriteResult returned error: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(result.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Result is not valid CSV: %v", err)
+	}
+	if len(rows) != job.Total+1 {
+		t.Fatalf("Expected header and %d rows, got %d rows", job.Total, len(rows))
+	}
+	for i, row := range rows[1:] {
+		if row[0] != fmt.Sprint(i+1) {
+			t.Fatalf("Row %d has id %s, rows are out of order", i+1, row[0])
+		}
+	}
+
+	cache.Delete(jobResultKey(job.ID, 0))
+	if err := jobService.WriteResult(context.Background(), job.ID, io.Discard); err != ErrJobNotFound {
+		t.Errorf("WriteResult without result = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestJobServiceStaleLease(t *testing.T) {
+	cache := NewMockCacheService()
+	jobService := NewJobService(nil, cache, 2)
+	jobService.leaseTimeout = time.Minute
+
+	now := time.Now().UTC()
+	tests := []struct {
+		name       string
+		status     string
+		lease      time.Time
+		wantStatus string
+	}{
+		{"Running with a fresh lease", JobStatusRunning, now, JobStatusRunning},
+		{"Running with a stale lease", JobStatusRunning, now.Add(-time.Hour), JobStatusFailed},
+		{"Queued with a stale lease", JobStatusQueued, now.Add(-time.Hour), JobStatusFailed},
+		{"Completed with a stale lease", JobStatusCompleted, now.Add(-time.Hour), JobStatusCompleted},
+		{"Running without a lease", JobStatusRunning, time.Time{}, JobStatusFailed},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := fmt.Sprintf("job-%d", i)
+			cache.Set(jobKey(id), models.Job{ID: id, Status: tt.status, UpdatedAt: now.Add(-time.Hour)})
+			if !tt.lease.IsZero() {
+				cache.Set(jobLeaseKey(id), tt.lease)
+			}
+
+			job, err := jobService.GetJob(context.Background(), id)
+			if err != nil {
+				t.Fatalf("GetJob returned error: %v", err)
+			}
+			if job.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", job.Status, tt.wantStatus)
+			}
+			if stored, _ := GetAs[models.Job](cache, jobKey(id)); stored.Status != tt.wantStatus {
+				t.Errorf("Stored status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestJobResultRowCoordinates(t *testing.T) {
+	column := func(row []string, name string) string {
+		for i, field := range jobResultColumns {
+			if field == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("no %s column", name)
+		return ""
+	}
+
+	onEquator := jobResultRow(models.BatchValidateResult{ValidateAddressResponse: models.ValidateAddressResponse{
+		Data: &models.AddressData{Latitude: coordinate(0), Longitude: coordinate(-78.4678)},
+	}})
+	if column(onEquator, "latitude") != "0" || column(onEquator, "longitude") != "-78.4678" {
+		t.Errorf("Coordinates = (%q, %q), want (0, -78.4678)", column(onEquator, "latitude"), column(onEquator, "longitude"))
+	}
+
+	withoutCoordinates := jobResultRow(models.BatchValidateResult{ValidateAddressResponse: models.ValidateAddressResponse{
+		Data: &models.AddressData{City: "Austin"},
+	}})
+	if column(withoutCoordinates, "latitude") != "" || column(withoutCoordinates, "longitude") != "" {
+		t.Errorf("Coordinates = (%q, %q), want empty", column(withoutCoordinates, "latitude"), column(withoutCoordinates, "longitude"))
+	}
+}
+
+func TestJobServiceRejectsInvalidCSV(t *testing.T) {
+	cache := NewMockCacheService()
+	jobService := NewJobService(NewValidatorService(NewGeocodingService(nil, cache), cache, DefaultGazetteer()), cache, 2)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Empty file", ""},
+		{"Missing address column", "id,street\n1,Main Street\n"},
+		{"Header only", "id,address\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jobService.CreateJob(context.Background(), strings.NewReader(tt.input), models.ValidationOptions{}); err == nil {
+				t.Error("Expected error for invalid CSV")
+			}
+		})
+	}
+
+	if _, err := jobService.GetJob(context.Background(), "missing"); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestJobServiceTenantIsolation(t *testing.T) {
+	var calls int32
+	server := newGeoapifyTestServer(t, &calls)
+
+	cache := NewMockCacheService()
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL}),
+	}, cache)
+	jobService := NewJobService(NewValidatorService(geocodingService, cache, DefaultGazetteer()), cache, 2)
+
+	tenants, err := ParseTenants(strings.NewReader(testTenants))
+	if err != nil {
+		t.Fatalf("ParseTenants() error = %v", err)
+	}
+	acme, _ := tenants.Lookup("acme-token")
+	globex, _ := tenants.Lookup("globex-token")
+	acmeCtx := WithTenant(context.Background(), acme)
+
+	job, err := jobService.CreateJob(acmeCtx, strings.NewReader("address\n\"123 Main St, San Francisco, CA\"\n"), models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("CreateJob returned error: %v", err)
+	}
+	if job.Tenant != "acme" {
+		t.Errorf("Tenant = %q, want acme", job.Tenant)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err = jobService.GetJob(acmeCtx, job.ID)
+		if err != nil {
+			t.Fatalf("GetJob returned error: %v", err)
+		}
+		if job.Status == JobStatusCompleted || job.Status == JobStatusFailed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job did not complete in time, status %s", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := jobService.WriteResult(acmeCtx, job.ID, io.Discard); err != nil {
+		t.Errorf("WriteResult for the owner returned error: %v", err)
+	}
+
+	others := map[string]context.Context{
+		"other tenant":   WithTenant(context.Background(), globex),
+		"main API token": context.Background(),
+	}
+	for name, ctx := range others {
+		if _, err := jobService.GetJob(ctx, job.ID); err != ErrJobNotFound {
+			t.Errorf("GetJob with the %s = %v, want ErrJobNotFound", name, err)
+		}
+		if err := jobService.WriteResult(ctx, job.ID, io.Discard); err != ErrJobNotFound {
+			t.Errorf("WriteResult with the %s = %v, want ErrJobNotFound", name, err)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const WarningProvidersUnavailable = "PROVIDERS_UNAVAILABLE"
+
+var countryNames = []string{"united states of america", "united states", "usa", "us", "u.s.a", "u.s"}
+
+var fractionPattern = regexp.MustCompile(`^\d/\d$`)
+
+// ParseAddress splits a normalized US address into its components with local
+// rules only, so they are known even when no provider can be reached. Cities the
+// gazetteer knows are returned by their canonical name.
+func ParseAddress(address string, gazetteer *Gazetteer) *models.ParsedAddress {
+	return parseAddress(address, gazetteer, currentDictionaries())
+}
+
+// parseAddress is ParseAddress with the dictionaries the address was normalized
+// with.
+func parseAddress(address string, gazetteer *Gazetteer, dictionaries *DictionarySet) *models.ParsedAddress {
+	parsed := &models.ParsedAddress{}
+
+	words := strings.Fields(address)
+	hasStreet := true
+	if delivery := recognizeDeliveryLine(address); delivery != nil {
+		parsed.StreetName = delivery.standardized
+		words = strings.Fields(delivery.rest)
+		hasStreet = false
+	}
+	words = trimCountry(words)
+
+	locked := make(map[int]bool)
+	var unit *secondaryUnit
+	if hasStreet {
+		unit = findSecondaryUnit(words, dictionaries)
+	}
+	if unit != nil {
+		parsed.Unit = unit.String()
+		for i := unit.start; i < unit.end; i++ {
+			locked[i] = true
+		}
+	}
+
+	end := len(words)
+	if zip := findPostalCode(words, locked); zip != nil {
+		parsed.PostalCode, parsed.PostalCodePlus4 = zip.code, zip.plus4
+		end = zip.start
+	}
+
+	state, stateIndex := detectState(words[:end])
+	if state != "" {
+		parsed.State = state
+		end = stateIndex
+	}
+
+	cityStart := 0
+	switch {
+	case !hasStreet:
+	case unit != nil && unit.start < end:
+		parseStreetComponents(words[:unit.start], parsed, dictionaries)
+		cityStart = unit.end
+	default:
+		cityStart = streetLineEnd(words[:end], gazetteer, state, dictionaries)
+		parseStreetComponents(words[:cityStart], parsed, dictionaries)
+	}
+
+	if cityStart < end {
+		parsed.City = parseCity(words[cityStart:end], gazetteer, state, parsed.PostalCode)
+	}
+	return parsed
+}
+
+// trimCountry drops a trailing country name, which the parser has no field for.
+func trimCountry(words []string) []string {
+	for _, name := range countryNames {
+		n := len(strings.Fields(name))
+		if len(words) <= n {
+			continue
+		}
+		if strings.ToLower(strings.TrimRight(strings.Join(words[len(words)-n:], " "), ",.")) == name {
+			return words[:len(words)-n]
+		}
+	}
+	return words
+}
+
+// streetLineEnd finds where the street line ends and the city begins. The first
+// comma before the city is taken when there is one; otherwise a known city at the
+// end, and then the last street suffix, mark the boundary.
+func streetLineEnd(words []string, gazetteer *Gazetteer, state string, dictionaries *DictionarySet) int {
+	if len(words) == 0 {
+		return 0
+	}
+	for i, word := range words[:len(words)-1] {
+		if strings.HasSuffix(word, ",") {
+			return i + 1
+		}
+	}
+
+	for n := 3; n >= 1; n-- {
+		start := len(words) - n
+		if start < 2 {
+			continue
+		}
+		if isKnownCity(gazetteer, strings.TrimRight(strings.Join(words[start:], " "), ","), state) {
+			return start
+		}
+	}
+
+	for i := len(words) - 1; i > 1; i-- {
+		if dictionaries.isStreetSuffix(words[i]) {
+			if i+1 < len(words) {
+				if _, found := dictionaries.directionAbbreviation(words[i+1]); found {
+					return i + 2
+				}
+			}
+			return i + 1
+		}
+	}
+	return len(words)
+}
+
+func isKnownCity(gazetteer *Gazetteer, city string, state string) bool {
+	if state != "" {
+		_, found := gazetteer.Lookup(city, state)
+		return found
+	}
+	return len(gazetteer.Find(city)) > 0
+}
+
+// parseStreetComponents reads the house number from the front and the
+// directionals and suffix around the street name. A single word left is always
+// the name, so "North Ave" and "E St" keep their names.
+func parseStreetComponents(words []string, parsed *models.ParsedAddress, dictionaries *DictionarySet) {
+	line := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimRight(word, ","); word != "" {
+			line = append(line, word)
+		}
+	}
+
+	if len(line) > 0 && unicode.IsDigit(rune(line[0][0])) {
+		parsed.Number = line[0]
+		line = line[1:]
+		if len(line) > 1 && fractionPattern.MatchString(line[0]) {
+			parsed.Number += " " + line[0]
+			line = line[1:]
+		}
+	}
+
+	if len(line) > 1 {
+		if direction, found := dictionaries.directionAbbreviation(line[len(line)-1]); found {
+			parsed.PostDirection = direction
+			line = line[:len(line)-1]
+		}
+	}
+	if len(line) > 1 {
+		if suffix, found := dictionaries.streetSuffixAbbreviation(line[len(line)-1]); found {
+			parsed.Suffix = suffix
+			line = line[:len(line)-1]
+		}
+	}
+	if len(line) > 1 {
+		if direction, found := dictionaries.directionAbbreviation(line[0]); found {
+			parsed.PreDirection = direction
+			line = line[1:]
+		}
+	}
+
+	parsed.StreetName = strings.Join(line, " ")
+}
+
+// parseCity takes the last comma-separated part before the state, so a line such
+// as a building name between the street and the city is left out.
+func parseCity(words []string, gazetteer *Gazetteer, state string, postalCode string) string {
+	start := 0
+	for i, word := range words[:len(words)-1] {
+		if strings.HasSuffix(word, ",") {
+			start = i + 1
+		}
+	}
+	city := strings.TrimRight(strings.Join(words[start:], " "), ",")
+
+	if state == "" {
+		state, _ = stateForZIP(postalCode)
+	}
+	if entry, found := gazetteer.Lookup(city, state); found {
+		return entry.City
+	}
+	return city
+}
+
+func (d *DictionarySet) directionAbbreviation(word string) (string, bool) {
+	word = strings.ToLower(strings.TrimSuffix(word, "."))
+	if _, exists := d.directions[word]; exists {
+		return strings.ToUpper(word), true
+	}
+	for abbreviation, direction := range d.directions {
+		if direction == word && !strings.HasSuffix(abbreviation, ".") {
+			return strings.ToUpper(abbreviation), true
+		}
+	}
+	return "", false
+}
+
+func (d *DictionarySet) streetSuffixAbbreviation(word string) (string, bool) {
+	word = strings.ToLower(strings.TrimSuffix(word, "."))
+	for _, suffix := range d.suffixes {
+		if suffix.Name == word || containsString(suffix.Variants, word) {
+			return suffix.Abbreviation, true
+		}
+	}
+	return "", false
+}
+
+// parsedAddressData builds the result of degraded mode from the parsed
+// components.
+func parsedAddressData(parsed *models.ParsedAddress) *models.AddressData {
+	street := joinNonEmpty(" ", parsed.PreDirection, parsed.StreetName, parsed.Suffix, parsed.PostDirection)
+	postalCode := parsed.PostalCode
+	if parsed.PostalCodePlus4 != "" {
+		postalCode += "-" + parsed.PostalCodePlus4
+	}
+
+	data := &models.AddressData{
+		Street:          street,
+		Number:          parsed.Number,
+		Secondary:       parsed.Unit,
+		City:            parsed.City,
+		State:           parsed.State,
+		PostalCode:      parsed.PostalCode,
+		PostalCodePlus4: parsed.PostalCodePlus4,
+		Formatted: joinNonEmpty(", ",
+			joinNonEmpty(" ", parsed.Number, street),
+			parsed.Unit,
+			parsed.City,
+			joinNonEmpty(" ", parsed.State, postalCode),
+		),
+	}
+	if parsed.State != "" {
+		data.Country = "United States"
+	}
+	return data
+}
+
+// isDeliverable reports whether the parse has enough to be used as a result:
+// a street or delivery line and a city or ZIP code.
+func isDeliverable(parsed *models.ParsedAddress) bool {
+	return parsed.StreetName != "" && (parsed.City != "" || parsed.PostalCode != "")
+}
+
+func joinNonEmpty(separator string, parts ...string) string {
+	nonEmpty := []string{}
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, separator)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  models.ParsedAddress
+	}{
+		{
+			name:  "Full address",
+			input: "123 north Main street, APT 4B, san francisco, CA 94102-1234",
+			want: models.ParsedAddress{
+				Number: "123", PreDirection: "N", StreetName: "Main", Suffix: "ST", Unit: "APT 4B",
+				City: "San Francisco", State: "CA", PostalCode: "94102", PostalCodePlus4: "1234",
+			},
+		},
+		{
+			name:  "Postdirectional and unit without commas",
+			input: "1600 Pennsylvania avenue northwest STE 100, Washington, DC 20500",
+			want: models.ParsedAddress{
+				Number: "1600", StreetName: "Pennsylvania", Suffix: "AVE", PostDirection: "NW", Unit: "STE 100",
+				City: "Washington", State: "DC", PostalCode: "20500",
+			},
+		},
+		{
+			name:  "Known city without a comma",
+			input: "500 Congress avenue Austin, TX 78701",
+			want: models.ParsedAddress{
+				Number: "500", StreetName: "Congress", Suffix: "AVE", City: "Austin", State: "TX", PostalCode: "78701",
+			},
+		},
+		{
+			name:  "Known city named with a street suffix",
+			input: "100 Main street Colorado Springs, CO",
+			want:  models.ParsedAddress{Number: "100", StreetName: "Main", Suffix: "ST", City: "Colorado Springs", State: "CO"},
+		},
+		{
+			name:  "Street named like a direction",
+			input: "42 north avenue, Atlanta, GA",
+			want:  models.ParsedAddress{Number: "42", StreetName: "north", Suffix: "AVE", City: "Atlanta", State: "GA"},
+		},
+		{
+			name:  "Fractional house number and country",
+			input: "12 1/2 Elm street, Springfield, IL, USA",
+			want:  models.ParsedAddress{Number: "12 1/2", StreetName: "Elm", Suffix: "ST", City: "Springfield", State: "IL"},
+		},
+		{
+			name:  "PO box",
+			input: "PO BOX 45, Boise, ID 83701",
+			want:  models.ParsedAddress{StreetName: "PO BOX 45", City: "Boise", State: "ID", PostalCode: "83701"},
+		},
+		{
+			name:  "Street only",
+			input: "77 Massachusetts avenue",
+			want:  models.ParsedAddress{Number: "77", StreetName: "Massachusetts", Suffix: "AVE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAddress(tt.input, DefaultGazetteer()); *got != tt.want {
+				t.Errorf("ParseAddress(%q) = %+v, want %+v", tt.input, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAddressDegradedMode(t *testing.T) {
+	down := &fakeProvider{name: "down", capabilities: CapabilityGeocode, err: errors.New("timeout")}
+
+	tests := []struct {
+		name       string
+		providers  []Provider
+		input      string
+		wantStatus string
+		wantStreet string
+	}{
+		{
+			name:       "Providers down",
+			providers:  []Provider{down},
+			input:      "123 Main St, San Francisco, CA 94102",
+			wantStatus: "success",
+			wantStreet: "Main ST",
+		},
+		{
+			name:       "No provider configured",
+			input:      "123 Main St, San Francisco, CA 94102",
+			wantStatus: "success",
+			wantStreet: "Main ST",
+		},
+		{
+			name:       "Not enough to parse",
+			input:      "Main St",
+			wantStatus: "error",
+		},
+		{
+			name:       "Provider answered without a match",
+			providers:  []Provider{newFakeProvider("empty", "")},
+			input:      "123 Main St, San Francisco, CA 94102",
+			wantStatus: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewMockCacheService()
+			validatorService := NewValidatorService(NewGeocodingService(tt.providers, cache), cache, DefaultGazetteer())
+
+			result, err := validatorService.ValidateAddress(context.Background(), tt.input, models.ValidationOptions{})
+			if err != nil {
+				t.Fatalf("ValidateAddress returned error: %v", err)
+			}
+			if result.Status != tt.wantStatus || result.Verdict != VerdictUnverified {
+				t.Fatalf("Status = %q, Verdict = %q, want %q and unverified (error: %v)", result.Status, result.Verdict, tt.wantStatus, result.Error)
+			}
+			if result.Parsed == nil {
+				t.Fatal("Parsed is missing")
+			}
+			if tt.wantStatus != "success" {
+				return
+			}
+
+			if result.Data.Street != tt.wantStreet || result.Data.City != "San Francisco" {
+				t.Errorf("Data = %+v", result.Data)
+			}
+			last := result.Warnings[len(result.Warnings)-1]
+			if last.Code != WarningProvidersUnavailable {
+				t.Errorf("Warnings = %v, want %s", result.Warnings, WarningProvidersUnavailable)
+			}
+			if _, cached := cache.Get(validatorService.generateCacheKey("123 Main street, San Francisco, CA 94102", currentDictionaries().Version)); cached {
+				t.Error("Degraded result was cached")
+			}
+		})
+	}
+}
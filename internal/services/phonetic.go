@@ -0,0 +1,151 @@
+package services
+
+import "strings"
+
+// metaphone returns the Metaphone key of a word: a rough spelling of how it
+// sounds, so "filadelfia" and "philadelphia" both become "FLTLF". Words of
+// several parts get one key per part, separated by spaces.
+func metaphone(text string) string {
+	parts := strings.Fields(text)
+	keys := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if key := metaphoneWord(part); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return strings.Join(keys, " ")
+}
+
+func metaphoneWord(word string) string {
+	letters := make([]byte, 0, len(word))
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c >= 'A' && c <= 'Z' {
+			letters = append(letters, c)
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	switch string(letters[:min(2, len(letters))]) {
+	case "AE", "GN", "KN", "PN", "WR":
+		letters = letters[1:]
+	case "WH":
+		letters = append([]byte{'W'}, letters[2:]...)
+	}
+	if letters[0] == 'X' {
+		letters[0] = 'S'
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(letters) {
+			return 0
+		}
+		return letters[i]
+	}
+	isVowel := func(c byte) bool {
+		return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+	}
+	isFrontVowel := func(c byte) bool {
+		return c == 'E' || c == 'I' || c == 'Y'
+	}
+
+	var key strings.Builder
+	for i, c := range letters {
+		if i > 0 && c == letters[i-1] && c != 'C' {
+			continue
+		}
+		next := at(i + 1)
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				key.WriteByte('A')
+			}
+		case 'B':
+			if !(at(i-1) == 'M' && i == len(letters)-1) {
+				key.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case next == 'I' && at(i+2) == 'A', next == 'H' && at(i-1) != 'S':
+				key.WriteByte('X')
+			case isFrontVowel(next):
+				if at(i-1) != 'S' {
+					key.WriteByte('S')
+				}
+			default:
+				key.WriteByte('K')
+			}
+		case 'D':
+			if next == 'G' && isFrontVowel(at(i+2)) {
+				key.WriteByte('J')
+			} else {
+				key.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case next == 'H' && i+2 < len(letters) && !isVowel(at(i+2)):
+			case next == 'N' && (i+2 == len(letters) || string(letters[i+1:]) == "NED"):
+			case isFrontVowel(next):
+				key.WriteByte('J')
+			default:
+				key.WriteByte('K')
+			}
+		case 'H':
+			previous := at(i - 1)
+			if strings.IndexByte("CSPTG", previous) >= 0 {
+				continue
+			}
+			if isVowel(previous) && !isVowel(next) {
+				continue
+			}
+			key.WriteByte('H')
+		case 'K':
+			if at(i-1) != 'C' {
+				key.WriteByte('K')
+			}
+		case 'P':
+			if next == 'H' {
+				key.WriteByte('F')
+			} else {
+				key.WriteByte('P')
+			}
+		case 'Q':
+			key.WriteByte('K')
+		case 'S':
+			if next == 'H' || (next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A')) {
+				key.WriteByte('X')
+			} else {
+				key.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				key.WriteByte('X')
+			case next == 'H':
+				key.WriteByte('0')
+			case next == 'C' && at(i+2) == 'H':
+			default:
+				key.WriteByte('T')
+			}
+		case 'V':
+			key.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(next) {
+				key.WriteByte(c)
+			}
+		case 'X':
+			key.WriteString("KS")
+		case 'Z':
+			key.WriteByte('S')
+		default:
+			key.WriteByte(c)
+		}
+	}
+	return key.String()
+}
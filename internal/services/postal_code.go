@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const (
+	WarningInvalidZIP       = "INVALID_ZIP"
+	WarningUnknownZIP       = "UNKNOWN_ZIP"
+	WarningStateZIPMismatch = "STATE_ZIP_MISMATCH"
+)
+
+type zipPrefixRange struct {
+	low   string
+	high  string
+	state string
+}
+
+// zipPrefixStates are the 3-digit ZIP prefix ranges USPS assigns to each state,
+// territory and military "state". Prefixes inside a range that are not in use
+// are not listed separately.
+var zipPrefixStates = []zipPrefixRange{
+	{"005", "005", "NY"}, {"006", "007", "PR"}, {"008", "008", "VI"}, {"009", "009", "PR"},
+	{"010", "027", "MA"}, {"028", "029", "RI"}, {"030", "038", "NH"}, {"039", "049", "ME"},
+	{"050", "054", "VT"}, {"055", "055", "MA"}, {"056", "059", "VT"}, {"060", "069", "CT"},
+	{"070", "089", "NJ"}, {"090", "098", "AE"}, {"100", "149", "NY"}, {"150", "196", "PA"},
+	{"197", "199", "DE"}, {"200", "200", "DC"}, {"201", "201", "VA"}, {"202", "205", "DC"},
+	{"206", "219", "MD"}, {"220", "246", "VA"}, {"247", "268", "WV"}, {"270", "289", "NC"},
+	{"290", "299", "SC"}, {"300", "319", "GA"}, {"320", "339", "FL"}, {"340", "340", "AA"},
+	{"341", "349", "FL"}, {"350", "369", "AL"}, {"370", "385", "TN"}, {"386", "397", "MS"},
+	{"398", "399", "GA"}, {"400", "427", "KY"}, {"430", "459", "OH"}, {"460", "479", "IN"},
+	{"480", "499", "MI"}, {"500", "528", "IA"}, {"530", "549", "WI"}, {"550", "567", "MN"},
+	{"569", "569", "DC"}, {"570", "577", "SD"}, {"580", "588", "ND"}, {"590", "599", "MT"},
+	{"600", "629", "IL"}, {"630", "658", "MO"}, {"660", "679", "KS"}, {"680", "693", "NE"},
+	{"700", "714", "LA"}, {"716", "729", "AR"}, {"730", "732", "OK"}, {"733", "733", "TX"},
+	{"734", "749", "OK"}, {"750", "799", "TX"}, {"800", "816", "CO"}, {"820", "831", "WY"},
+	{"832", "838", "ID"}, {"840", "847", "UT"}, {"850", "865", "AZ"}, {"870", "884", "NM"},
+	{"885", "885", "TX"}, {"889", "898", "NV"}, {"900", "961", "CA"}, {"962", "966", "AP"},
+	{"967", "968", "HI"}, {"969", "969", "GU"}, {"970", "979", "OR"}, {"980", "994", "WA"},
+	{"995", "999", "AK"},
+}
+
+// postalCode is a ZIP code found in the input, written as one word
+// ("94102-1234") even when it was split ("94102 1234") or run together
+// ("941021234").
+type postalCode struct {
+	start int
+	end   int
+	code  string
+	plus4 string
+}
+
+func (p *postalCode) String() string {
+	if p.plus4 == "" {
+		return p.code
+	}
+	return p.code + "-" + p.plus4
+}
+
+// findPostalCode looks for the last ZIP or ZIP+4 code after the first word (the
+// house number), skipping locked words such as the secondary unit.
+func findPostalCode(words []string, locked map[int]bool) *postalCode {
+	for i := len(words) - 1; i > 0; i-- {
+		if locked[i] {
+			continue
+		}
+
+		word := strings.TrimRight(words[i], ",.")
+		switch {
+		case len(word) == 10 && word[5] == '-' && isNumeric(word[:5]) && isNumeric(word[6:]):
+			return &postalCode{start: i, end: i + 1, code: word[:5], plus4: word[6:]}
+		case len(word) == 9 && isNumeric(word):
+			return &postalCode{start: i, end: i + 1, code: word[:5], plus4: word[5:]}
+		case len(word) == 5 && isNumeric(word):
+			next := i + 1
+			if !strings.HasSuffix(words[i], ",") && next < len(words) && !locked[next] {
+				plus4 := strings.TrimRight(words[next], ",.")
+				if len(plus4) == 4 && isNumeric(plus4) {
+					return &postalCode{start: i, end: next + 1, code: word, plus4: plus4}
+				}
+			}
+			return &postalCode{start: i, end: i + 1, code: word}
+		}
+	}
+	return nil
+}
+
+// parsePostalCode reads a text that holds only a ZIP code, such as the postal
+// code field of a structured address.
+func parsePostalCode(text string) (*postalCode, bool) {
+	words := append([]string{""}, strings.Fields(text)...)
+	zip := findPostalCode(words, nil)
+	if zip == nil || zip.start != 1 || zip.end != len(words) {
+		return nil, false
+	}
+	zip.start--
+	zip.end--
+	return zip, true
+}
+
+// collapsePostalCode replaces the words of the ZIP code with its standard form
+// as a single word, keeping a trailing comma, and records the change.
+func collapsePostalCode(words []string, zip *postalCode, corrections *correctionRecorder) []string {
+	standard := zip.String()
+	if strings.TrimRight(strings.Join(words[zip.start:zip.end], " "), ",.") != standard {
+		corrections.add(CorrectionPostalCode, zip.start, zip.end, standard)
+	}
+	corrections.merge(zip.start, zip.end)
+
+	if strings.HasSuffix(words[zip.end-1], ",") {
+		standard += ","
+	}
+
+	collapsed := append([]string{}, words[:zip.start]...)
+	collapsed = append(collapsed, standard)
+	return append(collapsed, words[zip.end:]...)
+}
+
+// splitPostalCode separates the ZIP+4 add-on of a postal code returned by a
+// provider; codes in any other format are returned as they are.
+func splitPostalCode(postal string) (code string, plus4 string) {
+	if zip, found := parsePostalCode(postal); found {
+		return zip.code, zip.plus4
+	}
+	return postal, ""
+}
+
+func stateForZIP(code string) (string, bool) {
+	if len(code) < 3 || !isNumeric(code[:3]) {
+		return "", false
+	}
+
+	prefix := code[:3]
+	for _, r := range zipPrefixStates {
+		if prefix >= r.low && prefix <= r.high {
+			return r.state, true
+		}
+	}
+	return "", false
+}
+
+// postalCodeWarnings checks the ZIP code against the prefix table and, when the
+// input also names a state, against that state.
+func postalCodeWarnings(code string, state string) []models.Warning {
+	zipState, found := stateForZIP(code)
+	if !found {
+		return []models.Warning{{
+			Code:    WarningUnknownZIP,
+			Message: fmt.Sprintf("ZIP code %s is not assigned to any state", code),
+			Field:   "postal_code",
+		}}
+	}
+
+	if state != "" && state != zipState {
+		return []models.Warning{{
+			Code:    WarningStateZIPMismatch,
+			Message: fmt.Sprintf("ZIP code %s belongs to %s, not %s", code, zipState, state),
+			Field:   "postal_code",
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+func TestNormalizePostalCode(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		name           string
+		input          string
+		wantNormalized string
+		wantCode       string
+		wantPlus4      string
+		wantWarning    string
+	}{
+		{
+			name:           "5-digit code",
+			input:          "123 Main St, San Francisco, CA 94102",
+			wantNormalized: "123 Main street, San Francisco, CA 94102",
+			wantCode:       "94102",
+		},
+		{
+			name:           "ZIP+4 with hyphen",
+			input:          "123 Main St, San Francisco, CA 94102-1234",
+			wantNormalized: "123 Main street, San Francisco, CA 94102-1234",
+			wantCode:       "94102",
+			wantPlus4:      "1234",
+		},
+		{
+			name:           "ZIP+4 run together",
+			input:          "123 Main St, San Francisco, CA 941021234",
+			wantNormalized: "123 Main street, San Francisco, CA 94102-1234",
+			wantCode:       "94102",
+			wantPlus4:      "1234",
+		},
+		{
+			name:           "ZIP+4 split by a space",
+			input:          "123 Main St, San Francisco, CA 94102 1234",
+			wantNormalized: "123 Main street, San Francisco, CA 94102-1234",
+			wantCode:       "94102",
+			wantPlus4:      "1234",
+		},
+		{
+			name:           "House number is not a ZIP code",
+			input:          "12345 Main St",
+			wantNormalized: "12345 Main street",
+		},
+		{
+			name:           "ZIP code of another state",
+			input:          "100 Congress Ave, Austin, CA 10001",
+			wantNormalized: "100 Congress avenue, Austin, CA 10001",
+			wantCode:       "10001",
+			wantWarning:    WarningStateZIPMismatch,
+		},
+		{
+			name:           "Unassigned ZIP prefix",
+			input:          "1 Main St, Springfield, IL 00001",
+			wantNormalized: "1 Main street, Springfield, IL 00001",
+			wantCode:       "00001",
+			wantWarning:    WarningUnknownZIP,
+		},
+		{
+			name:           "PO box keeps the ZIP code",
+			input:          "PO Box 45, Boise, ID 83701-0045",
+			wantNormalized: "PO BOX 45, Boise, ID 83701-0045",
+			wantCode:       "83701",
+			wantPlus4:      "0045",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
+
+			if result.Normalized != tt.wantNormalized {
+				t.Errorf("Normalized = %q, want %q", result.Normalized, tt.wantNormalized)
+			}
+			if result.PostalCode != tt.wantCode || result.PostalCodePlus4 != tt.wantPlus4 {
+				t.Errorf("PostalCode = %q-%q, want %q-%q", result.PostalCode, result.PostalCodePlus4, tt.wantCode, tt.wantPlus4)
+			}
+
+			warning := ""
+			if len(result.Warnings) > 0 {
+				warning = result.Warnings[0].Code
+			}
+			if warning != tt.wantWarning {
+				t.Errorf("Warning = %q, want %q (warnings: %v)", warning, tt.wantWarning, result.Warnings)
+			}
+		})
+	}
+}
+
+func TestStateForZIP(t *testing.T) {
+	tests := []struct {
+		code      string
+		wantState string
+		wantFound bool
+	}{
+		{"94102", "CA", true},
+		{"02108", "MA", true},
+		{"05501", "MA", true},
+		{"73301", "TX", true},
+		{"09021", "AE", true},
+		{"20500", "DC", true},
+		{"99501", "AK", true},
+		{"00001", "", false},
+	}
+
+	for _, tt := range tests {
+		state, found := stateForZIP(tt.code)
+		if state != tt.wantState || found != tt.wantFound {
+			t.Errorf("stateForZIP(%q) = %q, %v, want %q, %v", tt.code, state, found, tt.wantState, tt.wantFound)
+		}
+	}
+}
+
+func TestValidateAddressPostalCodePlus4(t *testing.T) {
+	fake := &fakeProvider{
+		name:         "fake",
+		capabilities: CapabilityGeocode,
+		candidates: []models.Candidate{{
+			Address:  &models.AddressData{City: "San Francisco", State: "CA", PostalCode: "94102", Precision: PrecisionRooftop},
+			Provider: "fake",
+			Score:    0.9,
+		}},
+	}
+	cache := NewMockCacheService()
+	validatorService := NewValidatorService(NewGeocodingService([]Provider{fake}, cache), cache, DefaultGazetteer())
+
+	result, err := validatorService.ValidateAddress(context.Background(), "123 Main St, San Francisco, NY 941021234", models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	if result.Data.PostalCode != "94102" || result.Data.PostalCodePlus4 != "1234" {
+		t.Errorf("PostalCode = %q, PostalCodePlus4 = %q, want 94102 and 1234", result.Data.PostalCode, result.Data.PostalCodePlus4)
+	}
+	if len(result.Warnings) == 0 || result.Warnings[0].Code != WarningStateZIPMismatch {
+		t.Errorf("Warnings = %v, want %s first", result.Warnings, WarningStateZIPMismatch)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+type Capability uint8
+
+const (
+	CapabilityGeocode Capability = 1 << iota
+	CapabilityAutocomplete
+	CapabilityReverseGeocode
+	CapabilityStructuredGeocode
+)
+
+func (c Capability) Has(other Capability) bool {
+	return c&other == other
+}
+
+const (
+	PrecisionRooftop  = "rooftop"
+	PrecisionStreet   = "street"
+	PrecisionPostcode = "postcode"
+	PrecisionCity     = "city"
+	PrecisionRegion   = "region"
+)
+
+type Provider interface {
+	Name() string
+	Capabilities() Capability
+	Geocode(ctx context.Context, query string) ([]models.Candidate, error)
+}
+
+type Autocompleter interface {
+	Autocomplete(ctx context.Context, query string, limit int) ([]models.Suggestion, error)
+}
+
+type StructuredGeocoder interface {
+	GeocodeStructured(ctx context.Context, address models.StructuredAddress) ([]models.Candidate, error)
+}
+
+type ReverseGeocoder interface {
+	ReverseGeocode(ctx context.Context, latitude, longitude float64) ([]models.Candidate, error)
+}
+
+type ProviderOptions struct {
+	APIKey     string
+	BaseURL    string
+	MaxResults int
+	Client     *http.Client
+}
+
+type ProviderFactory func(opts ProviderOptions) Provider
+
+type ProviderRegistry struct {
+	factories map[string]ProviderFactory
+}
+
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{
+		factories: make(map[string]ProviderFactory),
+	}
+}
+
+func DefaultProviderRegistry() *ProviderRegistry {
+	registry := NewProviderRegistry()
+	registry.Register("geoapify", NewGeoapifyProvider)
+	registry.Register("smarty", NewSmartyProvider)
+	return registry
+}
+
+func (r *ProviderRegistry) Register(name string, factory ProviderFactory) {
+	r.factories[strings.ToLower(name)] = factory
+}
+
+func (r *ProviderRegistry) Create(name string, opts ProviderOptions) (Provider, error) {
+	factory, exists := r.factories[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("unknown geocoding provider %q (available: %s)", name, strings.Join(r.Names(), ", "))
+	}
+
+	return factory(opts), nil
+}
+
+func (r *ProviderRegistry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// siblingEndpoint swaps the last path segment of a provider URL,
+// e.g. .../geocode/search becomes .../geocode/autocomplete.
+func siblingEndpoint(baseURL, name string) string {
+	if i := strings.LastIndex(baseURL, "/"); i >= 0 {
+		return baseURL[:i+1] + name
+	}
+	return baseURL
+}
+
+func providerMaxResults(opts ProviderOptions) int {
+	if opts.MaxResults > 0 {
+		return opts.MaxResults
+	}
+	return 1
+}
+
+func providerClient(opts ProviderOptions) *http.Client {
+	if opts.Client != nil {
+		return opts.Client
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+	}
+}
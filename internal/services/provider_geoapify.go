@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const geoapifyDefaultBaseURL = "https://api.geoapify.com/v1/geocode/search"
+
+type GeoapifyProvider struct {
+	apiKey     string
+	baseURL    string
+	maxResults int
+	client     *http.Client
+}
+
+func NewGeoapifyProvider(opts ProviderOptions) Provider {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = geoapifyDefaultBaseURL
+	}
+
+	return &GeoapifyProvider{
+		apiKey:     opts.APIKey,
+		baseURL:    baseURL,
+		maxResults: providerMaxResults(opts),
+		client:     providerClient(opts),
+	}
+}
+
+type GeoapifyResponse struct {
+	Type     string            `json:"type"`
+	Features []GeoapifyFeature `json:"features"`
+	Query    GeoapifyQuery     `json:"query"`
+}
+
+type GeoapifyFeature struct {
+	Type       string             `json:"type"`
+	Properties GeoapifyProperties `json:"properties"`
+	Geometry   GeoapifyGeometry   `json:"geometry"`
+	Bbox       []float64          `json:"bbox"`
+}
+
+type GeoapifyProperties struct {
+	Country      string       `json:"country"`
+	CountryCode  string       `json:"country_code"`
+	State        string       `json:"state"`
+	StateCode    string       `json:"state_code"`
+	County       string       `json:"county"`
+	City         string       `json:"city"`
+	Postcode     string       `json:"postcode"`
+	Suburb       string       `json:"suburb"`
+	Street       string       `json:"street"`
+	HouseNumber  string       `json:"housenumber"`
+	Formatted    string       `json:"formatted"`
+	AddressLine1 string       `json:"address_line1"`
+	AddressLine2 string       `json:"address_line2"`
+	ResultType   string       `json:"result_type"`
+	Lon          *float64     `json:"lon"`
+	Lat          *float64     `json:"lat"`
+	Rank         GeoapifyRank `json:"rank"`
+}
+
+type GeoapifyRank struct {
+	Importance            float64 `json:"importance"`
+	Popularity            float64 `json:"popularity"`
+	Confidence            float64 `json:"confidence"`
+	ConfidenceCityLevel   float64 `json:"confidence_city_level"`
+	ConfidenceStreetLevel float64 `json:"confidence_street_level"`
+	MatchType             string  `json:"match_type"`
+}
+
+type GeoapifyGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type GeoapifyQuery struct {
+	Text   string                `json:"text"`
+	Parsed GeoapifyParsedAddress `json:"parsed"`
+}
+
+type GeoapifyParsedAddress struct {
+	HouseNumber  string `json:"housenumber"`
+	Street       string `json:"street"`
+	Postcode     string `json:"postcode"`
+	District     string `json:"district"`
+	City         string `json:"city"`
+	Country      string `json:"country"`
+	ExpectedType string `json:"expected_type"`
+}
+
+func (p *GeoapifyProvider) Name() string {
+	return "geoapify"
+}
+
+func (p *GeoapifyProvider) Capabilities() Capability {
+	return CapabilityGeocode | CapabilityAutocomplete | CapabilityReverseGeocode | CapabilityStructuredGeocode
+}
+
+func (p *GeoapifyProvider) Geocode(ctx context.Context, address string) ([]models.Candidate, error) {
+	params := url.Values{}
+	params.Add("text", address)
+	params.Add("limit", strconv.Itoa(p.maxResults))
+
+	geoapifyResp, err := p.fetch(ctx, p.baseURL, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.candidates(geoapifyResp), nil
+}
+
+func (p *GeoapifyProvider) GeocodeStructured(ctx context.Context, address models.StructuredAddress) ([]models.Candidate, error) {
+	number, street := parseStreetLine(address.Line1)
+
+	params := url.Values{}
+	for name, value := range map[string]string{
+		"housenumber": number,
+		"street":      street,
+		"city":        address.City,
+		"state":       address.State,
+		"postcode":    address.PostalCode,
+		"country":     address.Country,
+	} {
+		if value != "" {
+			params.Add(name, value)
+		}
+	}
+	params.Add("limit", strconv.Itoa(p.maxResults))
+
+	geoapifyResp, err := p.fetch(ctx, p.baseURL, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.candidates(geoapifyResp), nil
+}
+
+func (p *GeoapifyProvider) ReverseGeocode(ctx context.Context, latitude, longitude float64) ([]models.Candidate, error) {
+	params := url.Values{}
+	params.Add("lat", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Add("lon", strconv.FormatFloat(longitude, 'f', -1, 64))
+	params.Add("limit", strconv.Itoa(p.maxResults))
+
+	geoapifyResp, err := p.fetch(ctx, siblingEndpoint(p.baseURL, "reverse"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.candidates(geoapifyResp), nil
+}
+
+func (p *GeoapifyProvider) candidates(geoapifyResp *GeoapifyResponse) []models.Candidate {
+	candidates := make([]models.Candidate, 0, len(geoapifyResp.Features))
+	for _, feature := range geoapifyResp.Features {
+		address := geoapifyAddressData(feature)
+		candidates = append(candidates, models.Candidate{
+			Address:  address,
+			Provider: p.Name(),
+			Score:    geoapifyScore(feature.Properties.Rank, address.Precision),
+		})
+	}
+	return candidates
+}
+
+func (p *GeoapifyProvider) Autocomplete(ctx context.Context, query string, limit int) ([]models.Suggestion, error) {
+	params := url.Values{}
+	params.Add("text", query)
+	params.Add("limit", strconv.Itoa(limit))
+
+	geoapifyResp, err := p.fetch(ctx, siblingEndpoint(p.baseURL, "autocomplete"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.Suggestion, 0, len(geoapifyResp.Features))
+	for _, feature := range geoapifyResp.Features {
+		address := geoapifyAddressData(feature)
+		suggestions = append(suggestions, models.Suggestion{
+			Text:     address.Formatted,
+			Address:  address,
+			Provider: p.Name(),
+		})
+	}
+
+	return suggestions, nil
+}
+
+func (p *GeoapifyProvider) fetch(ctx context.Context, endpoint string, params url.Values) (*GeoapifyResponse, error) {
+	params.Set("apiKey", p.apiKey)
+
+	requestURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var geoapifyResp GeoapifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geoapifyResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &geoapifyResp, nil
+}
+
+func geoapifyAddressData(feature GeoapifyFeature) *models.AddressData {
+	props := feature.Properties
+
+	return &models.AddressData{
+		Street:      formatStreetAddress(props.HouseNumber, props.Street),
+		Number:      props.HouseNumber,
+		Secondary:   geoapifySecondary(props.AddressLine2),
+		City:        props.City,
+		State:       props.StateCode,
+		PostalCode:  props.Postcode,
+		County:      props.County,
+		Country:     props.Country,
+		Formatted:   props.Formatted,
+		Latitude:    props.Lat,
+		Longitude:   props.Lon,
+		Precision:   geoapifyPrecision(props.ResultType),
+		BoundingBox: geoapifyBoundingBox(feature.Bbox),
+	}
+}
+
+// geoapifySecondary keeps address_line2 only when it holds a unit; for most
+// results it is the city line.
+func geoapifySecondary(addressLine2 string) string {
+	if unit, found := parseSecondaryUnit(addressLine2, currentDictionaries()); found {
+		return unit.String()
+	}
+	return ""
+}
+
+func geoapifyScore(rank GeoapifyRank, precision string) float64 {
+	score := rank.Confidence
+	if score <= 0 {
+		score = 1
+	}
+	return roundScore(capByPrecision(score, precision))
+}
+
+func geoapifyPrecision(resultType string) string {
+	switch resultType {
+	case "building", "amenity":
+		return PrecisionRooftop
+	case "street":
+		return PrecisionStreet
+	case "postcode":
+		return PrecisionPostcode
+	case "suburb", "district", "city":
+		return PrecisionCity
+	case "county", "state", "country":
+		return PrecisionRegion
+	default:
+		return ""
+	}
+}
+
+// geoapifyBoundingBox converts a GeoJSON bbox ([lon1, lat1, lon2, lat2]).
+func geoapifyBoundingBox(bbox []float64) *models.BoundingBox {
+	if len(bbox) != 4 {
+		return nil
+	}
+
+	return &models.BoundingBox{
+		MinLatitude:  bbox[1],
+		MinLongitude: bbox[0],
+		MaxLatitude:  bbox[3],
+		MaxLongitude: bbox[2],
+	}
+}
+
+func formatStreetAddress(number, street string) string {
+	if number != "" && street != "" {
+		return street
+	}
+	if street != "" {
+		return street
+	}
+	return ""
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const smartyDefaultBaseURL = "https://us-autocomplete-pro.api.smarty.com/lookup"
+
+type SmartyProvider struct {
+	apiKey     string
+	baseURL    string
+	maxResults int
+	client     *http.Client
+}
+
+func NewSmartyProvider(opts ProviderOptions) Provider {
+	baseURL := strings.TrimSpace(opts.BaseURL)
+	if baseURL == "" {
+		baseURL = smartyDefaultBaseURL
+	}
+
+	return &SmartyProvider{
+		apiKey:     opts.APIKey,
+		baseURL:    baseURL,
+		maxResults: providerMaxResults(opts),
+		client:     providerClient(opts),
+	}
+}
+
+type SmartyResponse struct {
+	Suggestions []SmartySuggestion `json:"suggestions"`
+}
+
+type SmartySuggestion struct {
+	StreetLine string `json:"street_line"`
+	Secondary  string `json:"secondary"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	Zipcode    string `json:"zipcode"`
+	Entries    int    `json:"entries"`
+	Source     string `json:"source"`
+}
+
+// smartySourceOther marks suggestions from non-postal address data, whose house
+// numbers USPS does not deliver to.
+const smartySourceOther = "other"
+
+func (p *SmartyProvider) Name() string {
+	return "smarty"
+}
+
+func (p *SmartyProvider) Capabilities() Capability {
+	return CapabilityGeocode | CapabilityAutocomplete | CapabilityStructuredGeocode
+}
+
+func (p *SmartyProvider) Geocode(ctx context.Context, address string) ([]models.Candidate, error) {
+	smartyResp, err := p.lookup(ctx, p.searchParams(address, p.maxResults))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.candidates(smartyResp), nil
+}
+
+// GeocodeStructured searches by the street lines and uses the city, state and
+// ZIP code as filters, which Smarty applies strictly instead of guessing.
+func (p *SmartyProvider) GeocodeStructured(ctx context.Context, address models.StructuredAddress) ([]models.Candidate, error) {
+	search := strings.TrimSpace(address.Line1 + " " + address.Line2)
+	params := p.searchParams(search, p.maxResults)
+
+	switch {
+	case address.PostalCode != "":
+		params.Add("include_only_zip_codes", address.PostalCode)
+	case address.City != "" && address.State != "":
+		params.Add("include_only_cities", fmt.Sprintf("%s,%s", address.City, address.State))
+	case address.State != "":
+		params.Add("include_only_states", address.State)
+	}
+
+	smartyResp, err := p.lookup(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.candidates(smartyResp), nil
+}
+
+func (p *SmartyProvider) candidates(smartyResp *SmartyResponse) []models.Candidate {
+	candidates := make([]models.Candidate, 0, len(smartyResp.Suggestions))
+	for _, suggestion := range smartyResp.Suggestions {
+		address := smartyAddressData(suggestion)
+		candidates = append(candidates, models.Candidate{
+			Address:  address,
+			Provider: p.Name(),
+			Score:    smartyScore(suggestion, address.Precision),
+		})
+	}
+	return candidates
+}
+
+func (p *SmartyProvider) Autocomplete(ctx context.Context, query string, limit int) ([]models.Suggestion, error) {
+	smartyResp, err := p.lookup(ctx, p.searchParams(query, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.Suggestion, 0, len(smartyResp.Suggestions))
+	for _, suggestion := range smartyResp.Suggestions {
+		address := smartyAddressData(suggestion)
+		suggestions = append(suggestions, models.Suggestion{
+			Text:     address.Formatted,
+			Address:  address,
+			Provider: p.Name(),
+		})
+	}
+
+	return suggestions, nil
+}
+
+func (p *SmartyProvider) searchParams(search string, maxResults int) url.Values {
+	params := url.Values{}
+	params.Add("search", search)
+	params.Add("max_results", strconv.Itoa(maxResults))
+	return params
+}
+
+func (p *SmartyProvider) lookup(ctx context.Context, params url.Values) (*SmartyResponse, error) {
+	params.Set("key", p.apiKey)
+	params.Set("license", "us-autocomplete-pro-cloud")
+
+	requestURL := fmt.Sprintf("%s?%s", p.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Referer", "localhost:3000")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var smartyResp SmartyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&smartyResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &smartyResp, nil
+}
+
+func smartyAddressData(suggestion SmartySuggestion) *models.AddressData {
+	number, street := parseStreetLine(suggestion.StreetLine)
+
+	return &models.AddressData{
+		Street:     street,
+		Number:     number,
+		Secondary:  suggestion.Secondary,
+		City:       suggestion.City,
+		State:      suggestion.State,
+		PostalCode: suggestion.Zipcode,
+		County:     "",
+		Country:    "United States",
+		Formatted:  formatAddress(suggestion),
+		Precision:  smartyPrecision(suggestion, number),
+	}
+}
+
+// smartyPrecision derives the precision from the match metadata of the
+// suggestion, since Smarty returns no coordinates: only a USPS record that
+// resolves to a single delivery point is an exact address; a house number from
+// non-postal data, or a building whose unit is not known yet, is only located
+// on its street.
+func smartyPrecision(suggestion SmartySuggestion, number string) string {
+	if number == "" || suggestion.Source == smartySourceOther {
+		return PrecisionStreet
+	}
+	if suggestion.Entries > 1 {
+		return PrecisionStreet
+	}
+	return PrecisionRooftop
+}
+
+// smartyScore lowers the score of suggestions that expand into several units,
+// since the input did not say which one was meant.
+func smartyScore(suggestion SmartySuggestion, precision string) float64 {
+	score := 0.9
+	if suggestion.Entries > 1 {
+		score = 0.7
+	}
+	return roundScore(capByPrecision(score, precision))
+}
+
+func parseStreetLine(streetLine string) (number string, street string) {
+	parts := strings.Fields(streetLine)
+	if len(parts) == 0 {
+		return "", ""
+	}
+
+	firstPart := parts[0]
+	if isHouseNumber(firstPart) {
+		number = firstPart
+		if len(parts) > 1 {
+			street = strings.Join(parts[1:], " ")
+		}
+	} else {
+		street = streetLine
+	}
+
+	return number, street
+}
+
+func isHouseNumber(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func formatAddress(s SmartySuggestion) string {
+	parts := []string{s.StreetLine}
+
+	if s.Secondary != "" {
+		parts = append(parts, s.Secondary)
+	}
+
+	cityStateZip := fmt.Sprintf("%s, %s %s", s.City, s.State, s.Zipcode)
+	parts = append(parts, cityStateZip)
+
+	return strings.Join(parts, ", ")
+}
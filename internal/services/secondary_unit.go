@@ -0,0 +1,181 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+type secondaryUnit struct {
+	start      int
+	end        int
+	designator string
+	number     string
+	corrected  bool
+}
+
+// String is the standard form of the unit; "#" is written against its number
+// ("#12") so the unit stays a single word.
+func (u *secondaryUnit) String() string {
+	if u.number == "" {
+		return u.designator
+	}
+	if u.designator == "#" {
+		return u.designator + u.number
+	}
+	return u.designator + " " + u.number
+}
+
+// findSecondaryUnit looks for a unit designator after the first word (the house
+// number) followed by its unit number, e.g. "Apt 4B", "Suite 200" or "#12".
+// Designators are common words too ("Upper 5th Ave", "Lot 5 Rd"), so a unit
+// is only taken right after the street suffix or at the end of the street line;
+// "#" can be nothing else. Designators USPS allows without a number ("Rear",
+// "Bsmt") are only taken right after the street suffix.
+func findSecondaryUnit(words []string, dictionaries *DictionarySet) *secondaryUnit {
+	for i := 1; i < len(words); i++ {
+		word := strings.ToLower(strings.TrimRight(words[i], ",."))
+
+		if strings.HasPrefix(word, "#") && len(word) > 1 && isUnitNumber(word[1:]) {
+			return &secondaryUnit{start: i, end: i + 1, designator: "#", number: strings.ToUpper(word[1:])}
+		}
+
+		designator, corrected, found := lookupUnitDesignator(word)
+		if !found {
+			continue
+		}
+
+		next := i + 1
+		if next < len(words) && words[next] == "#" && designator != "#" {
+			next++
+		}
+
+		if !strings.HasSuffix(words[i], ",") && next < len(words) {
+			number := strings.TrimRight(words[next], ",.")
+			if isUnitNumber(number) && !(designator == "FL" && looksLikeZIP(number)) &&
+				(designator == "#" || followsStreetSuffix(words, i, dictionaries) || endsStreetLine(words, next+1)) {
+				return &secondaryUnit{start: i, end: next + 1, designator: designator, number: strings.ToUpper(number), corrected: corrected}
+			}
+		}
+
+		if RangelessUnitDesignators[designator] && !corrected && !strings.HasSuffix(words[i-1], ",") && dictionaries.isStreetSuffix(words[i-1]) {
+			return &secondaryUnit{start: i, end: i + 1, designator: designator}
+		}
+	}
+	return nil
+}
+
+// followsStreetSuffix reports whether the word at i comes right after the street
+// suffix, or after a directional that follows it ("Main St N Apt 4").
+func followsStreetSuffix(words []string, i int, dictionaries *DictionarySet) bool {
+	previous := i - 1
+	if previous > 1 && !strings.HasSuffix(words[previous], ",") {
+		if _, found := dictionaries.directionAbbreviation(words[previous]); found {
+			previous--
+		}
+	}
+	return previous > 0 && !strings.HasSuffix(words[previous], ",") && dictionaries.isStreetSuffix(words[previous])
+}
+
+// endsStreetLine reports whether the words before end close the street line:
+// nothing, a comma or a ZIP code comes after them.
+func endsStreetLine(words []string, end int) bool {
+	return end >= len(words) || strings.HasSuffix(words[end-1], ",") || looksLikeZIP(strings.TrimRight(words[end], ",."))
+}
+
+// parseSecondaryUnit standardizes a text that holds only a unit, such as an
+// address line 2.
+func parseSecondaryUnit(text string, dictionaries *DictionarySet) (*secondaryUnit, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 1 {
+		designator, exists := SecondaryUnitDesignators[strings.ToLower(strings.TrimRight(fields[0], ",."))]
+		if exists && RangelessUnitDesignators[designator] {
+			return &secondaryUnit{start: 0, end: 1, designator: designator}, true
+		}
+	}
+
+	words := append([]string{""}, fields...)
+	unit := findSecondaryUnit(words, dictionaries)
+	if unit == nil || unit.start != 1 || unit.end != len(words) {
+		return nil, false
+	}
+	unit.start--
+	unit.end--
+	return unit, true
+}
+
+// collapseSecondaryUnit replaces the words of the unit with its standard form as a
+// single word, keeping a trailing comma, and records the change.
+func collapseSecondaryUnit(words []string, unit *secondaryUnit, corrections *correctionRecorder) []string {
+	standard := unit.String()
+
+	kind := CorrectionSecondaryUnit
+	if unit.corrected {
+		kind = CorrectionTypo
+	}
+	corrections.add(kind, unit.start, unit.end, standard)
+	corrections.merge(unit.start, unit.end)
+
+	if strings.HasSuffix(words[unit.end-1], ",") {
+		standard += ","
+	}
+
+	collapsed := append([]string{}, words[:unit.start]...)
+	collapsed = append(collapsed, standard)
+	return append(collapsed, words[unit.end:]...)
+}
+
+func lookupUnitDesignator(word string) (designator string, corrected bool, found bool) {
+	if designator, exists := SecondaryUnitDesignators[word]; exists {
+		return designator, false, true
+	}
+
+	if len(word) < 4 || !isAlpha(word) {
+		return "", false, false
+	}
+
+	maxDistance := 1
+	if len(word) > 6 {
+		maxDistance = 2
+	}
+	if match, found := unitNameDictionary.FindClosest(word, maxDistance); found {
+		return SecondaryUnitDesignators[match], true, true
+	}
+	return "", false, false
+}
+
+func isUnitNumber(s string) bool {
+	if len(s) == 1 && unicode.IsLetter(rune(s[0])) {
+		return true
+	}
+	for _, c := range s {
+		if unicode.IsDigit(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeZIP keeps "Miami FL 33101" from being read as a floor number.
+func looksLikeZIP(s string) bool {
+	if len(s) == 10 && s[5] == '-' {
+		return isNumeric(s[:5]) && isNumeric(s[6:])
+	}
+	return len(s) == 5 && isNumeric(s)
+}
+
+func (d *DictionarySet) isStreetSuffix(word string) bool {
+	word = strings.ToLower(strings.TrimRight(word, ",."))
+	if _, exists := d.streetAbbreviations[word]; exists {
+		return true
+	}
+	return d.streetTypes[word]
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if !unicode.IsLetter(c) {
+			return false
+		}
+	}
+	return len(s) > 0
+}
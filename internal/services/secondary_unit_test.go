@@ -0,0 +1,158 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestNormalizeSecondaryUnit(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		name           string
+		input          string
+		wantNormalized string
+		wantSecondary  string
+		wantTypo       bool
+	}{
+		{
+			name:           "Apartment",
+			input:          "123 Main St Apt 4B",
+			wantNormalized: "123 Main street APT 4B",
+			wantSecondary:  "APT 4B",
+		},
+		{
+			name:           "Suite before city",
+			input:          "500 Market St Ste 200, San Francisco, CA",
+			wantNormalized: "500 Market street STE 200, San Francisco, CA",
+			wantSecondary:  "STE 200",
+		},
+		{
+			name:           "Pound sign",
+			input:          "10 Oak Ave #12",
+			wantNormalized: "10 Oak avenue #12",
+			wantSecondary:  "#12",
+		},
+		{
+			name:           "Pound sign apart from its number",
+			input:          "10 Oak Ave # 12",
+			wantNormalized: "10 Oak avenue #12",
+			wantSecondary:  "#12",
+		},
+		{
+			name:           "Designator typo",
+			input:          "10 Oak Ave Apartmnt 3",
+			wantNormalized: "10 Oak avenue APT 3",
+			wantSecondary:  "APT 3",
+			wantTypo:       true,
+		},
+		{
+			name:           "Room is not corrected to road",
+			input:          "77 Elm Street Room 5",
+			wantNormalized: "77 Elm Street RM 5",
+			wantSecondary:  "RM 5",
+		},
+		{
+			name:           "Designator without number after suffix",
+			input:          "100 Main St Rear",
+			wantNormalized: "100 Main street REAR",
+			wantSecondary:  "REAR",
+		},
+		{
+			name:           "Florida ZIP is not a floor",
+			input:          "200 Biscayne Blvd Miami FL 33131",
+			wantNormalized: "200 Biscayne boulevard Miami FL 33131",
+			wantSecondary:  "",
+		},
+		{
+			name:           "Street named like a designator",
+			input:          "100 Front St",
+			wantNormalized: "100 Front street",
+			wantSecondary:  "",
+		},
+		{
+			name:           "Upper in the street name",
+			input:          "100 Upper 5th Ave",
+			wantNormalized: "100 Upper 5th avenue",
+			wantSecondary:  "",
+		},
+		{
+			name:           "Lower in the street name",
+			input:          "12 Lower 3rd St",
+			wantNormalized: "12 Lower 3rd street",
+			wantSecondary:  "",
+		},
+		{
+			name:           "Front and a number in the street name",
+			input:          "10 Front 2 St",
+			wantNormalized: "10 Front 2 street",
+			wantSecondary:  "",
+		},
+		{
+			name:           "Lot in the street name",
+			input:          "12 Lot 5 Rd",
+			wantNormalized: "12 Lot 5 road",
+			wantSecondary:  "",
+		},
+		{
+			name:           "Unit after a trailing directional",
+			input:          "10 Main St N Apt 4",
+			wantNormalized: "10 Main street north APT 4",
+			wantSecondary:  "APT 4",
+		},
+		{
+			name:           "Unit at the end of a street line without suffix",
+			input:          "10 Broadway Apt 4, New York, NY",
+			wantNormalized: "10 Broadway APT 4, New York, NY",
+			wantSecondary:  "APT 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
+
+			if result.Normalized != tt.wantNormalized {
+				t.Errorf("Normalized = %q, want %q", result.Normalized, tt.wantNormalized)
+			}
+			if result.Secondary != tt.wantSecondary {
+				t.Errorf("Secondary = %q, want %q", result.Secondary, tt.wantSecondary)
+			}
+
+			typo := false
+			for _, correction := range result.Corrections {
+				if correction.Kind == CorrectionTypo {
+					typo = true
+				}
+			}
+			if typo != tt.wantTypo {
+				t.Errorf("Typo correction = %v, want %v (corrections: %v)", typo, tt.wantTypo, result.Corrections)
+			}
+		})
+	}
+}
+
+func TestParseSecondaryUnit(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		found bool
+	}{
+		{"Suite 200", "STE 200", true},
+		{"apt # 4b", "APT 4B", true},
+		{"#7", "#7", true},
+		{"Penthouse", "PH", true},
+		{"Unit", "", false},
+		{"San Francisco, CA 94102, United States of America", "", false},
+	}
+
+	for _, tt := range tests {
+		unit, found := parseSecondaryUnit(tt.input, currentDictionaries())
+		if found != tt.found {
+			t.Errorf("parseSecondaryUnit(%q) found = %v, want %v", tt.input, found, tt.found)
+			continue
+		}
+		if found && unit.String() != tt.want {
+			t.Errorf("parseSecondaryUnit(%q) = %q, want %q", tt.input, unit.String(), tt.want)
+		}
+	}
+}
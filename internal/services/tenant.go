@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Tenant is an API customer with its own words on top of the global
+// dictionaries: aliases of its local places, protected words that are never
+// corrected (private street names) and cities the gazetteer does not know.
+type Tenant struct {
+	Name           string            `json:"name"`
+	Token          string            `json:"token"`
+	Aliases        map[string]string `json:"aliases"`
+	ProtectedWords []string          `json:"protected_words"`
+	Cities         []string          `json:"cities"`
+
+	version   string
+	protected map[string]bool
+	places    *cityDictionary
+}
+
+// TenantRegistry finds the tenant of an API token.
+type TenantRegistry struct {
+	byToken map[string]*Tenant
+}
+
+type tenantsFile struct {
+	Tenants []*Tenant `json:"tenants"`
+}
+
+// ParseTenants reads a JSON tenants file. Names and tokens must be unique, and
+// the words are checked like those of a dictionary file.
+func ParseTenants(r io.Reader) (*TenantRegistry, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var file tenantsFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid tenants file: %w", err)
+	}
+
+	registry := &TenantRegistry{byToken: make(map[string]*Tenant)}
+	names := make(map[string]bool)
+	for i, tenant := range file.Tenants {
+		if tenant == nil || tenant.Name == "" || tenant.Token == "" {
+			return nil, fmt.Errorf("tenants[%d]: name and token are required", i)
+		}
+		if names[tenant.Name] {
+			return nil, fmt.Errorf("tenants[%d]: duplicate name %q", i, tenant.Name)
+		}
+		if _, exists := registry.byToken[tenant.Token]; exists {
+			return nil, fmt.Errorf("tenants[%d]: %s has the token of another tenant", i, tenant.Name)
+		}
+		if err := tenant.index(); err != nil {
+			return nil, fmt.Errorf("tenants[%d]: %s: %w", i, tenant.Name, err)
+		}
+		names[tenant.Name] = true
+		registry.byToken[tenant.Token] = tenant
+	}
+	return registry, nil
+}
+
+func LoadTenantsFile(path string) (*TenantRegistry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tenants file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseTenants(file)
+}
+
+// index validates the words of the tenant and builds its city dictionary. The
+// version is a hash of the words, so cached results are dropped when they change.
+func (t *Tenant) index() error {
+	aliases := make(map[string]string, len(t.Aliases))
+	for alias, city := range t.Aliases {
+		alias, city = normalizeDictionaryWord(alias), normalizeDictionaryWord(city)
+		if !isDictionaryName(alias) || !isDictionaryName(city) {
+			return fmt.Errorf("invalid alias %q: %q", alias, city)
+		}
+		aliases[alias] = city
+	}
+
+	cities := make([]string, len(t.Cities))
+	for i, city := range t.Cities {
+		cities[i] = normalizeDictionaryWord(city)
+		if !isDictionaryName(cities[i]) {
+			return fmt.Errorf("cities[%d]: invalid city %q", i, city)
+		}
+	}
+
+	protected := make([]string, len(t.ProtectedWords))
+	t.protected = make(map[string]bool, len(t.ProtectedWords))
+	for i, word := range t.ProtectedWords {
+		protected[i] = normalizeDictionaryWord(word)
+		if protected[i] == "" || strings.Contains(protected[i], " ") {
+			return fmt.Errorf("protected_words[%d]: invalid word %q", i, word)
+		}
+		t.protected[protected[i]] = true
+	}
+
+	content, err := json.Marshal([]any{aliases, protected, cities})
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(content)
+	t.version = hex.EncodeToString(hash[:6])
+
+	t.places = &cityDictionary{
+		names:     NewDictionary(cities),
+		multiWord: NewDictionary(filterMultiWord(cities)),
+		aliases:   aliases,
+	}
+	return nil
+}
+
+// Lookup returns the tenant of a token.
+func (r *TenantRegistry) Lookup(token string) (*Tenant, bool) {
+	if r == nil {
+		return nil, false
+	}
+	tenant, exists := r.byToken[token]
+	return tenant, exists
+}
+
+// TenantContext returns ctx with the tenant of a token, for the auth middleware.
+func (r *TenantRegistry) TenantContext(ctx context.Context, token string) (context.Context, bool) {
+	tenant, found := r.Lookup(token)
+	if !found {
+		return ctx, false
+	}
+	return WithTenant(ctx, tenant), true
+}
+
+// Len returns the number of tenants.
+func (r *TenantRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.byToken)
+}
+
+// protects reports whether the tenant protects a word from every correction.
+func (t *Tenant) protects(word string) bool {
+	return t != nil && t.protected[strings.ToLower(strings.TrimRight(word, ",."))]
+}
+
+// cities puts the places of the tenant on top of a city dictionary.
+func (t *Tenant) cities(base *cityDictionary) *cityDictionary {
+	if t == nil {
+		return base
+	}
+	cities := *base
+	cities.overlay = t.places
+	return &cities
+}
+
+// tenantName is the name of a tenant, or "" for the main API token.
+func tenantName(t *Tenant) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context that carries the tenant of the request.
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the request, or nil for the main API
+// token.
+func TenantFromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(tenantContextKey{}).(*Tenant)
+	return tenant
+}
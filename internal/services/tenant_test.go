@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
+)
+
+const testTenants = `{
+	"tenants": [
+		{
+			"name": "acme",
+			"token": "acme-token",
+			"aliases": {"The Springs": "Colorado Springs"},
+			"protected_words": ["Bakersfeld"],
+			"cities": ["Oaklind", "Hidden Valley Lake"]
+		},
+		{"name": "globex", "token": "globex-token"}
+	]
+}`
+
+func TestParseTenants(t *testing.T) {
+	tenants, err := ParseTenants(strings.NewReader(testTenants))
+	if err != nil {
+		t.Fatalf("ParseTenants() error = %v", err)
+	}
+	if tenants.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", tenants.Len())
+	}
+	if tenant, found := tenants.Lookup("acme-token"); !found || tenant.Name != "acme" {
+		t.Errorf("Lookup(acme-token) = %+v, %v", tenant, found)
+	}
+	if _, found := tenants.Lookup("unknown"); found {
+		t.Errorf("Lookup(unknown) found a tenant")
+	}
+
+	invalid := []struct {
+		name string
+		data string
+	}{
+		{"Unknown field", `{"tenants": [{"name": "a", "token": "t", "city": ["x"]}]}`},
+		{"Missing token", `{"tenants": [{"name": "a"}]}`},
+		{"Duplicate name", `{"tenants": [{"name": "a", "token": "t1"}, {"name": "a", "token": "t2"}]}`},
+		{"Duplicate token", `{"tenants": [{"name": "a", "token": "t"}, {"name": "b", "token": "t"}]}`},
+		{"Digits in a city", `{"tenants": [{"name": "a", "token": "t", "cities": ["route 66"]}]}`},
+		{"Protected phrase", `{"tenants": [{"name": "a", "token": "t", "protected_words": ["rodeo drive"]}]}`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTenants(strings.NewReader(tt.data)); err == nil {
+				t.Errorf("ParseTenants(%s) accepted an invalid file", tt.data)
+			}
+		})
+	}
+}
+
+func TestNormalizeInputTenant(t *testing.T) {
+	tenants, err := ParseTenants(strings.NewReader(testTenants))
+	if err != nil {
+		t.Fatalf("ParseTenants() error = %v", err)
+	}
+	acme, _ := tenants.Lookup("acme-token")
+	globex, _ := tenants.Lookup("globex-token")
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		name       string
+		input      string
+		want       string
+		wantGlobal string
+	}{
+		{
+			name:       "Protected word",
+			input:      "1200 Truxtun Ave, Bakersfeld, CA",
+			want:       "1200 Truxtun avenue, Bakersfeld, CA",
+			wantGlobal: "1200 Truxtun avenue, bakersfield, CA",
+		},
+		{
+			name:       "Tenant alias",
+			input:      "1 Main St, The Springs, CO",
+			want:       "1 Main street, colorado springs, CO",
+			wantGlobal: "1 Main street, The Springs, CO",
+		},
+		{
+			name:       "Tenant city kept over a close gazetteer city",
+			input:      "1 Main St, Oaklind, CA",
+			want:       "1 Main street, Oaklind, CA",
+			wantGlobal: "1 Main street, oakland, CA",
+		},
+		{
+			name:       "Typo in a tenant city",
+			input:      "1 Main St, Hiden Valley Lake, CA",
+			want:       "1 Main street, hidden valley lake, CA",
+			wantGlobal: "1 Main street, Hiden Valley Lake, CA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validatorService.normalizeInput(tt.input, currentDictionaries(), acme).Normalized; got != tt.want {
+				t.Errorf("normalizeInput(%q, acme) = %q, want %q", tt.input, got, tt.want)
+			}
+			if got := validatorService.normalizeInput(tt.input, currentDictionaries(), globex).Normalized; got != tt.wantGlobal {
+				t.Errorf("normalizeInput(%q, globex) = %q, want %q", tt.input, got, tt.wantGlobal)
+			}
+			if got := validatorService.normalizeInput(tt.input, currentDictionaries(), nil).Normalized; got != tt.wantGlobal {
+				t.Errorf("normalizeInput(%q) = %q, want %q", tt.input, got, tt.wantGlobal)
+			}
+		})
+	}
+
+	structured, _ := validatorService.normalizeStructuredInput(models.StructuredAddress{Line1: "1200 Truxtun Ave", City: "Bakersfeld", State: "CA"}, currentDictionaries(), acme)
+	if structured.City != "Bakersfeld" {
+		t.Errorf("normalizeStructuredInput() City = %q, want the protected word", structured.City)
+	}
+}
+
+func TestValidateAddressTenantCacheIsolation(t *testing.T) {
+	tenants, err := ParseTenants(strings.NewReader(testTenants))
+	if err != nil {
+		t.Fatalf("ParseTenants() error = %v", err)
+	}
+	acme, _ := tenants.Lookup("acme-token")
+	globex, _ := tenants.Lookup("globex-token")
+	acmeCtx := WithTenant(context.Background(), acme)
+	globexCtx := WithTenant(context.Background(), globex)
+
+	cache := NewMockCacheService()
+	provider := newFakeProvider("fake", "Acme City")
+	validatorService := NewValidatorService(NewGeocodingService([]Provider{provider}, cache), cache, DefaultGazetteer())
+
+	const address = "123 Main St, San Francisco, CA"
+	validate := func(ctx context.Context) *models.ValidateAddressResponse {
+		t.Helper()
+		response, err := validatorService.ValidateAddress(ctx, address, models.ValidationOptions{})
+		if err != nil || response.Data == nil {
+			t.Fatalf("ValidateAddress() = %+v, %v", response, err)
+		}
+		return response
+	}
+
+	if got := validate(acmeCtx).Data.City; got != "Acme City" {
+		t.Fatalf("City = %q, want Acme City", got)
+	}
+
+	provider.candidates = []models.Candidate{{Address: &models.AddressData{City: "Globex City"}, Provider: "fake"}}
+	for name, ctx := range map[string]context.Context{"globex": globexCtx, "main API token": context.Background()} {
+		calls := provider.calls
+		if got := validate(ctx).Data.City; got != "Globex City" {
+			t.Errorf("City for %s = %q, want a fresh result rather than the one cached for acme", name, got)
+		}
+		if provider.calls != calls+1 {
+			t.Errorf("the provider was not called for %s", name)
+		}
+	}
+
+	calls := provider.calls
+	if got := validate(acmeCtx).Data.City; got != "Acme City" || provider.calls != calls {
+		t.Errorf("acme got %q with %d provider calls, want its own cached result", got, provider.calls-calls)
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	tenants, _ := ParseTenants(strings.NewReader(testTenants))
+	acme, _ := tenants.Lookup("acme-token")
+
+	ctx := WithTenant(context.Background(), acme)
+	if TenantFromContext(ctx) != acme || TenantFromContext(context.Background()) != nil {
+		t.Errorf("TenantFromContext() did not return the tenant of the context")
+	}
+
+	if ctx, found := tenants.TenantContext(context.Background(), "acme-token"); !found || TenantFromContext(ctx) != acme {
+		t.Errorf("TenantContext(acme-token) did not carry acme")
+	}
+	if _, found := tenants.TenantContext(context.Background(), "unknown"); found {
+		t.Errorf("TenantContext(unknown) found a tenant")
+	}
+	var none *TenantRegistry
+	if _, found := none.TenantContext(context.Background(), "acme-token"); found {
+		t.Errorf("TenantContext() without tenants found a tenant")
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenRole is the part of a free-form address a word stands in, from its
+// position. Each correction only runs on the words of its role, so a street name
+// close to a suffix ("Drake", "Lance") or to a city is left as written.
+type tokenRole int
+
+const (
+	roleOther tokenRole = iota
+	roleNumber
+	roleDirection
+	roleStreetName
+	roleSuffix
+	roleUnit
+	roleCity
+	roleState
+	rolePostalCode
+)
+
+// assignTokenRoles reads the layout of an address whose secondary unit and ZIP
+// code are already collapsed into one word each (unit and zip are their indexes,
+// or -1). The state and ZIP code are taken from the end, the street line from the
+// front, and the words between them are the city. Without a street line (the
+// rest of a PO box line) everything before the state is the city. It also returns
+// the detected state.
+func assignTokenRoles(words []string, unit, zip int, hasStreet bool, gazetteer *Gazetteer, dictionaries *DictionarySet) ([]tokenRole, string) {
+	roles := make([]tokenRole, len(words))
+	end := len(words)
+	if zip >= 0 {
+		roles[zip] = rolePostalCode
+		end = zip
+	}
+	if unit >= 0 {
+		roles[unit] = roleUnit
+	}
+
+	state, stateStart, stateEnd := findStateSpan(words[:end], hasStreet, dictionaries)
+	if state != "" {
+		for i := stateStart; i < stateEnd; i++ {
+			roles[i] = roleState
+		}
+		end = stateStart
+	}
+
+	cityStart := 0
+	if hasStreet {
+		streetEnd := end
+		if unit > 0 && unit < end {
+			streetEnd, cityStart = unit, unit+1
+		} else {
+			streetEnd = findStreetEnd(words[:end], gazetteer, state, dictionaries)
+			cityStart = streetEnd
+		}
+		assignStreetRoles(words, roles, 0, streetEnd, dictionaries)
+	}
+
+	for i := cityStart; i < end; i++ {
+		if roles[i] == roleOther {
+			roles[i] = roleCity
+		}
+	}
+	return roles, state
+}
+
+// findStateSpan returns the state of the address and the indexes of its words.
+// detectState needs a comma before the state; without one, a state code at the
+// very end (words stop before the ZIP code) is taken ("San Antonio TX",
+// "Louisville KY 40202"), even when it is also a suffix. Only a suffix right
+// after a lone street name ("123 Oak Ct") stays in the street line.
+func findStateSpan(words []string, hasStreet bool, dictionaries *DictionarySet) (string, int, int) {
+	if state, start := detectState(words); state != "" {
+		end := start + 1
+		if end < len(words) {
+			if _, exists := StateAbbreviations[strings.ToLower(words[start])+" "+strings.ToLower(strings.TrimRight(words[end], ",."))]; exists {
+				end++
+			}
+		}
+		return state, start, end
+	}
+
+	last := len(words) - 1
+	if last < 1 {
+		return "", -1, -1
+	}
+	lower := strings.ToLower(strings.TrimRight(words[last], ",."))
+	if len(lower) != 2 || (hasStreet && dictionaries.isStreetSuffix(lower) && !isTrailingStateCode(words, last, dictionaries)) {
+		return "", -1, -1
+	}
+	if state, found := NormalizeUSState(lower); found {
+		return state, last, last + 1
+	}
+	return "", -1, -1
+}
+
+// isTrailingStateCode reports whether the state code at the end of the words
+// (cut before the ZIP code) follows more than a lone street name. "KY", "MT",
+// "WY" and "CT" are also USPS suffix variants, but in "Louisville KY 40202" they
+// are the state; in "123 Oak Ct" no city is left before it, so it is a suffix.
+func isTrailingStateCode(words []string, i int, dictionaries *DictionarySet) bool {
+	lower := strings.ToLower(strings.TrimRight(words[i], ",."))
+	if _, exists := USStates[lower]; !exists {
+		return false
+	}
+	return i-streetNameStart(words[:i], dictionaries) >= 2
+}
+
+// findStreetEnd is streetLineEnd, and when neither a comma, a known city nor a
+// suffix marks the end of the street line, the first word after the street name
+// that is close to a suffix does ("12 Elm Stret Springfield").
+func findStreetEnd(words []string, gazetteer *Gazetteer, state string, dictionaries *DictionarySet) int {
+	end := streetLineEnd(words, gazetteer, state, dictionaries)
+	if end < len(words) || len(words) == 0 || dictionaries.isStreetSuffix(words[len(words)-1]) {
+		return end
+	}
+
+	for i := streetNameStart(words, dictionaries) + 1; i < len(words)-1; i++ {
+		if dictionaries.isSuffixLike(words[i]) {
+			if _, found := dictionaries.directionAbbreviation(words[i+1]); found {
+				return i + 2
+			}
+			return i + 1
+		}
+	}
+	return end
+}
+
+// streetNameStart skips the house number and a leading directional.
+func streetNameStart(words []string, dictionaries *DictionarySet) int {
+	start := 0
+	if start < len(words) && startsWithDigit(words[start]) {
+		start++
+		if start+1 < len(words) && fractionPattern.MatchString(words[start]) {
+			start++
+		}
+	}
+	if start+1 < len(words) {
+		if _, found := dictionaries.directionAbbreviation(words[start]); found {
+			start++
+		}
+	}
+	return start
+}
+
+// assignStreetRoles labels the words of a street line like parseStreetComponents
+// splits it. The suffix is the last word, after a trailing directional, when a
+// street name is left before it and the word is a suffix or a typo of one; a
+// name after a directional ("N Lance") is never taken for a suffix, so a lone
+// word is always the name, and names without a suffix ("El Paseo", "Las Olas")
+// are kept whole.
+func assignStreetRoles(words []string, roles []tokenRole, start, end int, dictionaries *DictionarySet) {
+	line := []int{}
+	for i := start; i < end; i++ {
+		if roles[i] == roleOther {
+			line = append(line, i)
+		}
+	}
+
+	if len(line) > 0 && startsWithDigit(words[line[0]]) {
+		roles[line[0]] = roleNumber
+		line = line[1:]
+		if len(line) > 1 && fractionPattern.MatchString(words[line[0]]) {
+			roles[line[0]] = roleNumber
+			line = line[1:]
+		}
+	}
+
+	if len(line) > 1 {
+		if _, found := dictionaries.directionAbbreviation(strings.TrimRight(words[line[len(line)-1]], ",")); found {
+			roles[line[len(line)-1]] = roleDirection
+			line = line[:len(line)-1]
+		}
+	}
+	if len(line) > 1 {
+		if _, found := dictionaries.directionAbbreviation(words[line[0]]); found {
+			roles[line[0]] = roleDirection
+			line = line[1:]
+		}
+	}
+	if len(line) > 1 && dictionaries.isSuffixLike(words[line[len(line)-1]]) {
+		roles[line[len(line)-1]] = roleSuffix
+		line = line[:len(line)-1]
+	}
+
+	for _, i := range line {
+		roles[i] = roleStreetName
+	}
+}
+
+// isSuffixLike accepts a suffix and a word within the suffix typo threshold.
+// A word that only sounds like one ("Paseo" and "pass") is a street name.
+func (d *DictionarySet) isSuffixLike(word string) bool {
+	if d.isStreetSuffix(word) {
+		return true
+	}
+	_, strategy, found := d.correctStreetType(word)
+	return found && strategy == MatchEditDistance
+}
+
+func startsWithDigit(word string) bool {
+	return word != "" && unicode.IsDigit(rune(word[0]))
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAssignTokenRoles(t *testing.T) {
+	tests := []struct {
+		input     string
+		hasStreet bool
+		want      []tokenRole
+		wantState string
+	}{
+		{
+			input:     "123 N Main Stret, Springfeld, IL",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleDirection, roleStreetName, roleSuffix, roleCity, roleState},
+			wantState: "IL",
+		},
+		{
+			input:     "9 Lance, Austin, TX",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleStreetName, roleCity, roleState},
+			wantState: "TX",
+		},
+		{
+			input:     "300 Alamo Plaza San Antono TX",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleStreetName, roleSuffix, roleCity, roleCity, roleState},
+			wantState: "TX",
+		},
+		{
+			input:     "12 Elm Stret Springfield",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleStreetName, roleSuffix, roleCity},
+		},
+		{
+			input:     "10 El Paseo, Palm Desert, CA",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleStreetName, roleStreetName, roleCity, roleCity, roleState},
+			wantState: "CA",
+		},
+		{
+			input:     "123 Oak Ct",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleStreetName, roleSuffix},
+		},
+		{
+			input:     "500 W Jefferson St Louisville KY 40202",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleDirection, roleStreetName, roleSuffix, roleCity, roleState, rolePostalCode},
+			wantState: "KY",
+		},
+		{
+			input:     "100 Main St Hartford CT",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleStreetName, roleSuffix, roleCity, roleState},
+			wantState: "CT",
+		},
+		{
+			input:     "100 Main St Helena MT 59601",
+			hasStreet: true,
+			want:      []tokenRole{roleNumber, roleStreetName, roleSuffix, roleCity, roleState, rolePostalCode},
+			wantState: "MT",
+		},
+		{
+			input:     "Cheyenne WY",
+			hasStreet: false,
+			want:      []tokenRole{roleCity, roleState},
+			wantState: "WY",
+		},
+		{
+			input:     "Boise, Idaho",
+			hasStreet: false,
+			want:      []tokenRole{roleCity, roleState},
+			wantState: "ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			words := strings.Fields(tt.input)
+			zip := -1
+			if looksLikeZIP(words[len(words)-1]) {
+				zip = len(words) - 1
+			}
+			roles, state := assignTokenRoles(words, -1, zip, tt.hasStreet, DefaultGazetteer(), currentDictionaries())
+			if !reflect.DeepEqual(roles, tt.want) || state != tt.wantState {
+				t.Errorf("assignTokenRoles(%q) = %v, %q, want %v, %q", tt.input, roles, state, tt.want, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestNormalizeInputStreetNames(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"9 Lance, Austin, TX", "9 Lance, Austin, TX"},
+		{"40 N Lance, Austin, TX", "40 north Lance, Austin, TX"},
+		{"15 Drake Ave, Denver, CO", "15 Drake avenue, Denver, CO"},
+		{"800 Parkway Plaza, Cary, NC", "800 Parkway Plaza, Cary, NC"},
+		{"9 Rodeo Drive, Beverly Hills, CA", "9 Rodeo Drive, Beverly Hills, CA"},
+		{"20 Lance Stret, Austin, TX", "20 Lance street, Austin, TX"},
+		{"5 Sacramnto Way, Sacramnto, CA", "5 Sacramnto Way, sacramento, CA"},
+		{"10 El Paseo, Palm Desert, CA", "10 El Paseo, Palm Desert, CA"},
+		{"10 El Paseo Palm Desert CA", "10 El Paseo Palm Desert CA"},
+		{"1 Broadway, New York, NY", "1 Broadway, New York, NY"},
+		{"350 W Broadway, Salt Lake City, UT", "350 west Broadway, Salt Lake City, UT"},
+		{"10 Las Olas, Fort Lauderdale, FL", "10 Las Olas, Fort Lauderdale, FL"},
+		{"100 E Las Olas, Fort Lauderdale, FL", "100 east Las Olas, Fort Lauderdale, FL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
+			if result.Normalized != tt.want {
+				t.Errorf("normalizeInput(%q) = %q, want %q (corrections: %v)", tt.input, result.Normalized, tt.want, result.Corrections)
+			}
+		})
+	}
+}
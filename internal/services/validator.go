@@ -4,9 +4,13 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/henrique/address-validator/internal/models"
 )
@@ -14,37 +18,170 @@ import (
 type ValidatorService struct {
 	geocodingService *GeocodingService
 	cache            Cache
+	gazetteer        *Gazetteer
 }
 
-func NewValidatorService(geocodingService *GeocodingService, cache Cache) *ValidatorService {
+func NewValidatorService(geocodingService *GeocodingService, cache Cache, gazetteer *Gazetteer) *ValidatorService {
 	return &ValidatorService{
 		geocodingService: geocodingService,
 		cache:            cache,
+		gazetteer:        gazetteer,
 	}
 }
 
-func (s *ValidatorService) ValidateAddress(ctx context.Context, address string) (*models.ValidateAddressResponse, error) {
-	normalized := s.normalizeInput(address)
+func (s *ValidatorService) ValidateAddress(ctx context.Context, address string, opts models.ValidationOptions) (*models.ValidateAddressResponse, error) {
+	if military, found := recognizeMilitaryAddress(address); found {
+		return s.validateMilitaryAddress(military, opts), nil
+	}
 
-	cacheKey := s.generateCacheKey(normalized.Normalized)
-	if cached, found := s.cache.Get(cacheKey); found {
-		if result, ok := cached.(*models.ValidateAddressResponse); ok {
-			return result, nil
-		}
+	tenant := TenantFromContext(ctx)
+	dictionaries := currentDictionaries()
+	normalized := s.normalizeInput(address, dictionaries, tenant)
+
+	cacheKey := s.generateCacheKey(normalized.Normalized, cacheNamespace(dictionaries, tenant))
+	return s.validate(cacheKey, normalized, dictionaries, opts, func() (*models.GeocodingResponse, error) {
+		return s.geocodingService.Geocode(ctx, normalized.Normalized)
+	})
+}
+
+func (s *ValidatorService) ValidateStructuredAddress(ctx context.Context, address models.StructuredAddress, opts models.ValidationOptions) (*models.ValidateAddressResponse, error) {
+	if military, found := recognizeMilitaryAddress(formatStructuredAddress(address)); found {
+		return s.validateMilitaryAddress(military, opts), nil
 	}
 
-	geocodingResult, err := s.geocodingService.Geocode(ctx, normalized.Normalized)
+	tenant := TenantFromContext(ctx)
+	dictionaries := currentDictionaries()
+	structured, normalized := s.normalizeStructuredInput(address, dictionaries, tenant)
+
+	cacheKey := s.generateStructuredCacheKey(structured, cacheNamespace(dictionaries, tenant))
+	return s.validate(cacheKey, normalized, dictionaries, opts, func() (*models.GeocodingResponse, error) {
+		return s.geocodingService.GeocodeStructured(ctx, structured)
+	})
+}
+
+func (s *ValidatorService) validate(cacheKey string, normalized *models.NormalizedInput, dictionaries *DictionarySet, opts models.ValidationOptions, geocode func() (*models.GeocodingResponse, error)) (*models.ValidateAddressResponse, error) {
+	if cached, found := GetAs[models.ValidateAddressResponse](s.cache, cacheKey); found {
+		return applyValidationOptions(cached, opts), nil
+	}
+
+	parsed := parseAddress(normalized.Normalized, s.gazetteer, dictionaries)
+
+	geocodingResult, err := geocode()
+	if errors.Is(err, ErrProvidersUnavailable) && isDeliverable(parsed) {
+		return applyValidationOptions(degradedResponse(normalized, parsed), opts), nil
+	}
 	if err != nil {
 		return &models.ValidateAddressResponse{
-			Status: "error",
-			Error:  fmt.Sprintf("Failed to validate address: %v", err),
+			Status:      "error",
+			Verdict:     VerdictUnverified,
+			AddressType: normalized.AddressType,
+			Parsed:      parsed,
+			Warnings:    normalized.Warnings,
+			Error:       fmt.Sprintf("Failed to validate address: %v", err),
 		}, nil
 	}
 
+	data := *geocodingResult.AddressData
+	if data.Secondary == "" {
+		data.Secondary = normalized.Secondary
+	}
+	data.PostalCode, data.PostalCodePlus4 = splitPostalCode(data.PostalCode)
+	if data.PostalCodePlus4 == "" && data.PostalCode == normalized.PostalCode {
+		data.PostalCodePlus4 = normalized.PostalCodePlus4
+	}
+	if normalized.DeliveryLine != "" {
+		data.Street = normalized.DeliveryLine
+		data.Number = ""
+	}
+
+	factor := correctionFactor(normalized.Corrections)
+	candidates := make([]models.Candidate, len(geocodingResult.Candidates))
+	for i, candidate := range geocodingResult.Candidates {
+		candidate.Score = roundScore(candidate.Score * factor)
+		candidates[i] = candidate
+	}
+	confidence := candidates[0].Score
+
 	response := &models.ValidateAddressResponse{
 		Status:      "success",
-		Data:        geocodingResult.AddressData,
-		Corrections: normalized.Changes,
+		Data:        &data,
+		Confidence:  confidence,
+		Verdict:     determineVerdict(confidence, candidates),
+		AddressType: normalized.AddressType,
+		Candidates:  candidates,
+		Parsed:      parsed,
+		Corrections: normalized.Corrections,
+		Warnings:    append(slices.Clip(normalized.Warnings), checkProviderConsistency(s.gazetteer, normalized, &data)...),
+	}
+
+	s.cache.Set(cacheKey, response)
+
+	return applyValidationOptions(response, opts), nil
+}
+
+// degradedResponse answers from the local parse when no provider could be
+// reached. Nothing was verified, so it is not cached and the next request tries
+// the providers again.
+func degradedResponse(normalized *models.NormalizedInput, parsed *models.ParsedAddress) *models.ValidateAddressResponse {
+	return &models.ValidateAddressResponse{
+		Status:      "success",
+		Data:        parsedAddressData(parsed),
+		Verdict:     VerdictUnverified,
+		AddressType: normalized.AddressType,
+		Parsed:      parsed,
+		Corrections: normalized.Corrections,
+		Warnings: append(slices.Clip(normalized.Warnings), models.Warning{
+			Code:    WarningProvidersUnavailable,
+			Message: "No geocoding provider could be reached; the address was parsed locally and not verified",
+		}),
+	}
+}
+
+// validateMilitaryAddress answers APO/FPO/DPO addresses locally: geocoding
+// providers cannot locate them, so only the format, the military state and its
+// ZIP range are checked.
+func (s *ValidatorService) validateMilitaryAddress(military *militaryAddress, opts models.ValidationOptions) *models.ValidateAddressResponse {
+	if err := military.validate(); err != nil {
+		return &models.ValidateAddressResponse{
+			Status:      "error",
+			Verdict:     VerdictUnverified,
+			AddressType: AddressTypeMilitary,
+			Error:       fmt.Sprintf("Failed to validate address: %v", err),
+		}
+	}
+
+	data := military.addressData()
+	confidence := capByPrecision(1, data.Precision)
+	candidates := []models.Candidate{{Address: data, Provider: "local", Score: confidence}}
+
+	return applyValidationOptions(&models.ValidateAddressResponse{
+		Status:      "success",
+		Data:        data,
+		Confidence:  confidence,
+		Verdict:     determineVerdict(confidence, candidates),
+		AddressType: AddressTypeMilitary,
+		Candidates:  candidates,
+	}, opts)
+}
+
+func (s *ValidatorService) ReverseGeocode(ctx context.Context, latitude, longitude float64) (*models.ReverseGeocodeResponse, error) {
+	cacheKey := s.generateReverseCacheKey(latitude, longitude)
+	if cached, found := GetAs[models.ReverseGeocodeResponse](s.cache, cacheKey); found {
+		return cached, nil
+	}
+
+	geocodingResult, err := s.geocodingService.ReverseGeocode(ctx, latitude, longitude)
+	if err != nil {
+		return &models.ReverseGeocodeResponse{
+			Status: "error",
+			Error:  fmt.Sprintf("Failed to reverse geocode coordinates: %v", err),
+		}, nil
+	}
+
+	response := &models.ReverseGeocodeResponse{
+		Status:   "success",
+		Data:     geocodingResult.AddressData,
+		Provider: geocodingResult.Provider,
 	}
 
 	s.cache.Set(cacheKey, response)
@@ -52,88 +189,167 @@ func (s *ValidatorService) ValidateAddress(ctx context.Context, address string)
 	return response, nil
 }
 
+func applyValidationOptions(response *models.ValidateAddressResponse, opts models.ValidationOptions) *models.ValidateAddressResponse {
+	if response.Status == "success" && opts.MinConfidence > 0 && response.Confidence < opts.MinConfidence {
+		return &models.ValidateAddressResponse{
+			Status:      "error",
+			Confidence:  response.Confidence,
+			Verdict:     response.Verdict,
+			AddressType: response.AddressType,
+			Corrections: response.Corrections,
+			Warnings:    response.Warnings,
+			Error:       fmt.Sprintf("Address confidence %.2f is below min_confidence %.2f", response.Confidence, opts.MinConfidence),
+		}
+	}
+
+	result := *response
+	if len(result.Candidates) > opts.MaxCandidates {
+		result.Candidates = result.Candidates[:opts.MaxCandidates]
+	}
+	if len(result.Candidates) == 0 {
+		result.Candidates = nil
+	}
+	return &result
+}
+
 func (s *ValidatorService) NormalizeInput(input string) *models.NormalizedInput {
-	return s.normalizeInput(input)
+	return s.normalizeInput(input, currentDictionaries(), nil)
+}
+
+// normalizeInput standardizes PO box, rural route and highway contract lines
+// as a whole, so they never reach the street typo correction, and normalizes the
+// rest of the input as a street address. Every lookup reads the same dictionary
+// set, taken once per request, so a reload never mixes two versions; the words
+// of the tenant, if any, apply on top of it.
+func (s *ValidatorService) normalizeInput(input string, dictionaries *DictionarySet, tenant *Tenant) *models.NormalizedInput {
+	delivery := recognizeDeliveryLine(input)
+	if delivery == nil {
+		return s.normalizeStreetAddress(input, true, dictionaries, tenant)
+	}
+
+	rest := s.normalizeStreetAddress(delivery.rest, false, dictionaries, tenant)
+
+	prefix := input[:len(input)-len(delivery.rest)]
+	corrections := newCorrectionRecorder(input, "")
+	corrections.add(delivery.addressType, 0, len(strings.Fields(delivery.original)), delivery.standardized)
+	corrections.corrections = append(corrections.corrections, shiftCorrections(rest.Corrections, utf8.RuneCountInString(prefix), len(strings.Fields(prefix)))...)
+
+	normalized := delivery.standardized
+	if rest.Normalized != "" {
+		normalized += ", " + rest.Normalized
+	}
+
+	return &models.NormalizedInput{
+		Original:        input,
+		Normalized:      normalized,
+		AddressType:     delivery.addressType,
+		DeliveryLine:    delivery.standardized,
+		City:            rest.City,
+		State:           rest.State,
+		PostalCode:      rest.PostalCode,
+		PostalCodePlus4: rest.PostalCodePlus4,
+		Corrections:     corrections.corrections,
+		Warnings:        rest.Warnings,
+	}
 }
 
-func (s *ValidatorService) normalizeInput(input string) *models.NormalizedInput {
+func (s *ValidatorService) normalizeStreetAddress(input string, hasStreet bool, dictionaries *DictionarySet, tenant *Tenant) *models.NormalizedInput {
 	original := input
 	normalized := strings.TrimSpace(input)
-	changes := []string{}
+	corrections := newCorrectionRecorder(input, "")
 
 	words := strings.Fields(normalized)
+	locked := make(map[int]bool)
+	unitIndex := -1
+	unit := findSecondaryUnit(words, dictionaries)
+	if unit != nil {
+		words = collapseSecondaryUnit(words, unit, corrections)
+		locked[unit.start] = true
+		unitIndex = unit.start
+	}
+
+	zipIndex := -1
+	zip := findPostalCode(words, locked)
+	if zip != nil {
+		words = collapsePostalCode(words, zip, corrections)
+		locked[zip.start] = true
+		zipIndex = zip.start
+		if unitIndex > zip.start {
+			unitIndex -= zip.end - zip.start - 1
+		}
+	}
+
+	roles, state := assignTokenRoles(words, unitIndex, zipIndex, hasStreet, s.gazetteer, dictionaries)
+	cityState := state
+	if cityState == "" && zip != nil {
+		cityState, _ = stateForZIP(zip.code)
+	}
+	cities := tenant.cities(s.gazetteer.cities(cityState, dictionaries))
+
+	// Words the tenant protects are locked like the unit and the ZIP code.
 	for i, word := range words {
-		lower := strings.ToLower(word)
+		if tenant.protects(word) {
+			locked[i] = true
+		}
+	}
 
-		if expansion, exists := StreetAbbreviations[lower]; exists {
-			oldWord := word
-			words[i] = expansion
-			changes = append(changes, fmt.Sprintf("%s → %s", oldWord, expansion))
+	// The city passes only see the words in the city position.
+	cityLocked := make(map[int]bool)
+	for i, role := range roles {
+		if locked[i] || role != roleCity {
+			cityLocked[i] = true
+		}
+	}
+	correctCityAliases(words, cityLocked, cities, corrections)
+
+	for i, word := range words {
+		if locked[i] {
 			continue
 		}
 
-		if expansion, exists := DirectionAbbreviations[lower]; exists {
-			oldWord := word
-			words[i] = expansion
-			changes = append(changes, fmt.Sprintf("%s → %s", oldWord, expansion))
+		if roles[i] == roleState && IsValidUSState(strings.TrimRight(word, ",.")) {
 			continue
 		}
+
+		if expansion, kind, exists := dictionaries.expandAbbreviation(word); exists {
+			words[i] = expansion
+			corrections.add(kind, i, i+1, expansion)
+		}
 	}
-	normalized = strings.Join(words, " ")
 
-	words = strings.Fields(normalized)
-	for i, word := range words {
-		lower := strings.ToLower(strings.TrimRight(word, ",."))
+	correctMultiWordCities(words, cityLocked, cities, corrections)
 
-		if len(lower) < 4 || isNumeric(lower) {
+	for i, word := range words {
+		if word == "" {
 			continue
 		}
 
-		if match, found := FindClosestMatch(lower, CommonStreetTypes, 2); found {
-			if lower != match && !isCommonWord(lower) {
-				suffix := ""
-				if strings.HasSuffix(word, ",") {
-					suffix = ","
-				} else if strings.HasSuffix(word, ".") {
-					suffix = "."
-				}
-				words[i] = match + suffix
-				changes = append(changes, fmt.Sprintf("%s → %s (typo correction)", word, match+suffix))
-				continue
+		switch {
+		case roles[i] == roleSuffix && !locked[i]:
+			if corrected, strategy, found := dictionaries.correctStreetType(word); found {
+				words[i] = corrected
+				corrections.addMatch(CorrectionTypo, i, i+1, corrected, strategy)
 			}
-		}
-
-		if len(lower) > 5 {
-			if match, found := FindClosestMatch(lower, CommonCityNames, 2); found {
-				if lower != match {
-					suffix := ""
-					if strings.HasSuffix(word, ",") {
-						suffix = ","
-					}
-					words[i] = match + suffix
-					changes = append(changes, fmt.Sprintf("%s → %s (city correction)", word, match+suffix))
-				}
+		case roles[i] == roleCity && !cityLocked[i]:
+			if corrected, strategy, found := dictionaries.correctCityName(word, cities); found {
+				words[i] = corrected
+				corrections.addMatch(CorrectionCity, i, i+1, corrected, strategy)
 			}
 		}
 	}
-	normalized = strings.Join(words, " ")
 
-	words = strings.Fields(normalized)
 	for i, word := range words {
-		lower := strings.ToLower(strings.TrimRight(word, ",."))
-
-		isAfterComma := i > 0 && strings.HasSuffix(words[i-1], ",")
-		isTwoLetters := len(lower) == 2
-		isAtEnd := i == len(words)-1
+		if locked[i] || word == "" || roles[i] != roleState {
+			continue
+		}
 
-		isLikelyState := (isTwoLetters && (isAfterComma || isAtEnd)) ||
-			(isAfterComma && len(lower) > 3)
+		lower := strings.ToLower(strings.TrimRight(word, ",."))
 
-		if isLikelyState {
+		if isLikelyState(words, i) {
 			if stateAbbr, found := NormalizeUSState(lower); found {
 				if !strings.EqualFold(word, stateAbbr) {
 					words[i] = stateAbbr
-					changes = append(changes, fmt.Sprintf("%s → %s (state)", word, stateAbbr))
+					corrections.add(CorrectionState, i, i+1, stateAbbr)
 				}
 			}
 		}
@@ -143,11 +359,403 @@ func (s *ValidatorService) normalizeInput(input string) *models.NormalizedInput
 	normalized = regexp.MustCompile(`\s+`).ReplaceAllString(normalized, " ")
 	normalized = strings.TrimSpace(normalized)
 
-	return &models.NormalizedInput{
-		Original:   original,
-		Normalized: normalized,
-		Changes:    changes,
+	result := &models.NormalizedInput{
+		Original:    original,
+		Normalized:  normalized,
+		AddressType: AddressTypeStreet,
+		Corrections: corrections.corrections,
+	}
+	if unit != nil {
+		result.Secondary = unit.String()
+	}
+	if zip != nil {
+		result.PostalCode = zip.code
+		result.PostalCodePlus4 = zip.plus4
+	}
+
+	words = strings.Fields(normalized)
+	if state, index := detectState(words); state != "" {
+		result.State = state
+		result.City = cityBeforeState(words, index)
+	}
+	result.Warnings = checkInputConsistency(s.gazetteer, result)
+	return result
+}
+
+// normalizeStructuredInput applies each correction only to the field it belongs
+// to: units, abbreviations and street-type typos on the street lines, city typos
+// on the city and state names on the state.
+func (s *ValidatorService) normalizeStructuredInput(address models.StructuredAddress, dictionaries *DictionarySet, tenant *Tenant) (models.StructuredAddress, *models.NormalizedInput) {
+	corrections := []models.Correction{}
+	secondary := ""
+
+	normalizeStreetLine := func(field string, line string, unit *secondaryUnit) string {
+		recorder := newCorrectionRecorder(line, field)
+		words := strings.Fields(line)
+		roles := make([]tokenRole, len(words))
+		end := len(words)
+		if unit != nil {
+			words = collapseSecondaryUnit(words, unit, recorder)
+			secondary = unit.String()
+			roles = make([]tokenRole, len(words))
+			roles[unit.start] = roleUnit
+			if unit.start > 0 {
+				end = unit.start
+			}
+		}
+		assignStreetRoles(words, roles, 0, end, dictionaries)
+
+		for i, word := range words {
+			if roles[i] == roleUnit || tenant.protects(word) {
+				continue
+			}
+
+			if expansion, kind, exists := dictionaries.expandAbbreviation(word); exists {
+				words[i] = expansion
+				recorder.add(kind, i, i+1, expansion)
+				continue
+			}
+
+			if roles[i] != roleSuffix {
+				continue
+			}
+			if corrected, strategy, found := dictionaries.correctStreetType(word); found {
+				words[i] = corrected
+				recorder.addMatch(CorrectionTypo, i, i+1, corrected, strategy)
+			}
+		}
+
+		corrections = append(corrections, recorder.corrections...)
+		return strings.Join(words, " ")
+	}
+
+	addressType := AddressTypeStreet
+	deliveryLine := ""
+	line2Unit, _ := parseSecondaryUnit(address.Line2, dictionaries)
+
+	normalizedLine1 := ""
+	if delivery := recognizeDeliveryLine(address.Line1); delivery != nil && delivery.rest == "" {
+		recorder := newCorrectionRecorder(address.Line1, "line1")
+		recorder.addAll(delivery.addressType, delivery.standardized)
+		corrections = append(corrections, recorder.corrections...)
+
+		addressType = delivery.addressType
+		deliveryLine = delivery.standardized
+		normalizedLine1 = delivery.standardized
+	} else {
+		normalizedLine1 = normalizeStreetLine("line1", address.Line1, findSecondaryUnit(strings.Fields(address.Line1), dictionaries))
+	}
+
+	normalized := models.StructuredAddress{
+		Line1:      normalizedLine1,
+		Line2:      normalizeStreetLine("line2", address.Line2, line2Unit),
+		City:       strings.Join(strings.Fields(address.City), " "),
+		State:      strings.TrimSpace(address.State),
+		PostalCode: strings.TrimSpace(address.PostalCode),
+		Country:    strings.TrimSpace(address.Country),
+	}
+
+	if normalized.State != "" {
+		if stateAbbr, found := NormalizeUSState(normalized.State); found {
+			if !strings.EqualFold(normalized.State, stateAbbr) {
+				recorder := newCorrectionRecorder(address.State, "state")
+				recorder.addAll(CorrectionState, stateAbbr)
+				corrections = append(corrections, recorder.corrections...)
+			}
+			normalized.State = stateAbbr
+		}
 	}
+
+	var warnings []models.Warning
+	postalCode, postalCodePlus4 := "", ""
+	if normalized.PostalCode != "" {
+		if zip, found := parsePostalCode(normalized.PostalCode); found {
+			if zip.String() != normalized.PostalCode {
+				recorder := newCorrectionRecorder(address.PostalCode, "postal_code")
+				recorder.addAll(CorrectionPostalCode, zip.String())
+				corrections = append(corrections, recorder.corrections...)
+			}
+			normalized.PostalCode = zip.String()
+			postalCode, postalCodePlus4 = zip.code, zip.plus4
+		} else {
+			warnings = []models.Warning{{
+				Code:    WarningInvalidZIP,
+				Message: fmt.Sprintf("%q is not a 5-digit or ZIP+4 code", normalized.PostalCode),
+				Field:   "postal_code",
+			}}
+		}
+	}
+
+	state := normalized.State
+	if state == "" {
+		state, _ = stateForZIP(postalCode)
+	}
+	cities := tenant.cities(s.gazetteer.cities(state, dictionaries))
+
+	recorder := newCorrectionRecorder(address.City, "city")
+	if name, exists := cities.alias(strings.ToLower(normalized.City)); exists {
+		recorder.addAll(CorrectionCityAlias, name)
+		normalized.City = name
+	} else {
+		words := strings.Fields(normalized.City)
+		locked := make(map[int]bool)
+		for i, word := range words {
+			if tenant.protects(word) {
+				locked[i] = true
+			}
+		}
+		correctMultiWordCities(words, locked, cities, recorder)
+		for i, word := range words {
+			if locked[i] || word == "" {
+				continue
+			}
+
+			if corrected, strategy, found := dictionaries.correctCityName(word, cities); found {
+				words[i] = corrected
+				recorder.addMatch(CorrectionCity, i, i+1, corrected, strategy)
+			}
+		}
+		normalized.City = strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+	}
+	corrections = append(corrections, recorder.corrections...)
+
+	result := &models.NormalizedInput{
+		Original:        formatStructuredAddress(address),
+		Normalized:      formatStructuredAddress(normalized),
+		AddressType:     addressType,
+		DeliveryLine:    deliveryLine,
+		Secondary:       secondary,
+		City:            normalized.City,
+		State:           normalized.State,
+		PostalCode:      postalCode,
+		PostalCodePlus4: postalCodePlus4,
+		Corrections:     corrections,
+	}
+	result.Warnings = append(warnings, checkInputConsistency(s.gazetteer, result)...)
+	return normalized, result
+}
+
+func isLikelyState(words []string, i int) bool {
+	lower := strings.ToLower(strings.TrimRight(words[i], ",."))
+
+	isAfterComma := i > 0 && strings.HasSuffix(words[i-1], ",")
+	isTwoLetters := len(lower) == 2
+	isAtEnd := i == len(words)-1
+
+	return (isTwoLetters && (isAfterComma || isAtEnd)) ||
+		(isAfterComma && len(lower) > 3)
+}
+
+// isStatePosition is stricter than isLikelyState: "Ct" and "Mt" at the end of
+// "123 Oak Ct" are suffixes, but after the city ("Hartford, CT") they are states.
+func isStatePosition(words []string, i int) bool {
+	if i > 0 && strings.HasSuffix(words[i-1], ",") {
+		return true
+	}
+	return i == len(words)-1 && strings.Contains(strings.Join(words[:i], " "), ",")
+}
+
+func (d *DictionarySet) expandAbbreviation(word string) (string, string, bool) {
+	lower := strings.ToLower(word)
+	suffix := ""
+	if strings.HasSuffix(lower, ",") {
+		lower = strings.TrimSuffix(lower, ",")
+		suffix = ","
+	}
+
+	if expansion, exists := d.streetAbbreviations[lower]; exists {
+		return expansion + suffix, CorrectionAbbreviation, true
+	}
+
+	if expansion, exists := d.directions[lower]; exists {
+		return expansion + suffix, CorrectionDirection, true
+	}
+
+	return "", "", false
+}
+
+// detectState finds the state of a free-form address and the index of its first
+// word. It runs before any correction, so city typos are only matched against the
+// cities of that state.
+func detectState(words []string) (string, int) {
+	for i := len(words) - 1; i >= 0; i-- {
+		if !isStatePosition(words, i) {
+			continue
+		}
+
+		lower := strings.ToLower(strings.TrimRight(words[i], ",."))
+		if i > 0 {
+			if abbr, exists := StateAbbreviations[strings.ToLower(words[i-1])+" "+lower]; exists {
+				return abbr, i - 1
+			}
+		}
+		if isNumeric(lower) || looksLikeZIP(lower) {
+			continue
+		}
+		if abbr, found := NormalizeUSState(lower); found {
+			return abbr, i
+		}
+	}
+	return "", -1
+}
+
+// cityBeforeState returns the words between the state and the comma before them
+// ("123 Main street, San Francisco, CA" → "San Francisco"). Without that comma
+// the city cannot be told apart from the street, so nothing is returned.
+func cityBeforeState(words []string, stateIndex int) string {
+	start := stateIndex - 1
+	if start <= 0 {
+		return ""
+	}
+	for start > 0 && !strings.HasSuffix(words[start-1], ",") {
+		start--
+	}
+	if start == 0 {
+		return ""
+	}
+	return strings.TrimRight(strings.Join(words[start:stateIndex], " "), ",")
+}
+
+// correctStreetType fixes a misspelled suffix. It is only called on the word in
+// the suffix position, so street names close to a suffix are never rewritten.
+func (d *DictionarySet) correctStreetType(word string) (string, string, bool) {
+	lower := strings.ToLower(strings.TrimRight(word, ",."))
+
+	if len(lower) < 4 || isNumeric(lower) || d.stopWords[lower] {
+		return "", "", false
+	}
+
+	match, found := d.streetTypeIndex.FindBest(lower, streetTypeMaxDistance(lower))
+	if !found || lower == match.Word {
+		return "", "", false
+	}
+
+	suffix := ""
+	if strings.HasSuffix(word, ",") {
+		suffix = ","
+	} else if strings.HasSuffix(word, ".") {
+		suffix = "."
+	}
+	return match.Word + suffix, match.Strategy, true
+}
+
+// streetTypeMaxDistance only allows two edits on longer words; with the full
+// USPS suffix list, short names like "Denver" are two edits from a suffix.
+func streetTypeMaxDistance(word string) int {
+	if len(word) < 7 {
+		return 1
+	}
+	return 2
+}
+
+// correctCityAliases replaces a known alias in the city position, the words
+// right before a comma, with the name of the city ("Philly, PA" → "philadelphia,
+// PA"). It runs before the abbreviations are expanded so "St Louis" is matched as
+// written, and locks the name like correctMultiWordCities does.
+func correctCityAliases(words []string, locked map[int]bool, cities *cityDictionary, corrections *correctionRecorder) {
+	for n := 3; n >= 1; n-- {
+		for start := 0; start+n <= len(words); start++ {
+			end := start + n
+			if !strings.HasSuffix(words[end-1], ",") || !isCitySpan(words[start:end], locked, start) {
+				continue
+			}
+
+			span := strings.Join(words[start:end], " ")
+			name, exists := cities.alias(strings.ToLower(strings.TrimSuffix(span, ",")))
+			if !exists {
+				continue
+			}
+
+			for i := start; i < end; i++ {
+				locked[i] = true
+				words[i] = ""
+			}
+			words[end-1] = name + ","
+			corrections.add(CorrectionCityAlias, start, end, name)
+		}
+	}
+}
+
+// correctMultiWordCities matches spans of two or three adjacent words against
+// the multi-word city names ("Los Angelas" → "los angeles"). The name is written
+// in the last word of the span and the others are emptied, so the indexes used
+// by the later passes do not move. Matched spans are locked, even when already
+// spelled correctly, so their words are not corrected one by one.
+func correctMultiWordCities(words []string, locked map[int]bool, cities *cityDictionary, corrections *correctionRecorder) {
+	for n := 3; n >= 2; n-- {
+		for start := 0; start+n <= len(words); start++ {
+			end := start + n
+			if !isCitySpan(words[start:end], locked, start) {
+				continue
+			}
+
+			span := strings.Join(words[start:end], " ")
+			lower := strings.ToLower(strings.TrimRight(span, ",."))
+
+			match, found := cities.findMultiWord(lower, cityMaxDistance(lower))
+			if !found {
+				continue
+			}
+
+			for i := start; i < end; i++ {
+				locked[i] = true
+			}
+			if lower == match.Word {
+				continue
+			}
+
+			suffix := ""
+			if strings.HasSuffix(span, ",") {
+				suffix = ","
+			}
+			for i := start; i < end-1; i++ {
+				words[i] = ""
+			}
+			words[end-1] = match.Word + suffix
+			corrections.addMatch(CorrectionCity, start, end, match.Word, match.Strategy)
+		}
+	}
+}
+
+// isCitySpan only accepts words without digits, with a comma at most after the
+// last one, that are not already part of another match. Periods are allowed
+// inside the span for names like "St. Louis".
+func isCitySpan(words []string, locked map[int]bool, start int) bool {
+	for i, word := range words {
+		if word == "" || locked[start+i] || strings.ContainsAny(word, "0123456789") {
+			return false
+		}
+		if i < len(words)-1 && strings.HasSuffix(word, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+func cityMaxDistance(name string) int {
+	if len(name) < 8 {
+		return 1
+	}
+	return 2
+}
+
+func (d *DictionarySet) correctCityName(word string, cities *cityDictionary) (string, string, bool) {
+	lower := strings.ToLower(strings.TrimRight(word, ",."))
+
+	if len(lower) <= 5 || isNumeric(lower) || d.stopWords[lower] {
+		return "", "", false
+	}
+
+	match, found := cities.findName(lower, 2)
+	if !found || lower == match.Word {
+		return "", "", false
+	}
+
+	suffix := ""
+	if strings.HasSuffix(word, ",") {
+		suffix = ","
+	}
+	return match.Word + suffix, match.Strategy, true
 }
 
 func isNumeric(s string) bool {
@@ -159,18 +767,90 @@ func isNumeric(s string) bool {
 	return len(s) > 0
 }
 
-func isCommonWord(word string) bool {
-	commonWords := map[string]bool{
-		"main": true, "park": true, "oak": true, "pine": true, "maple": true,
-		"elm": true, "cedar": true, "lake": true, "hill": true, "view": true,
-		"center": true, "first": true, "second": true, "third": true,
-		"north": true, "south": true, "east": true, "west": true,
-		"new": true, "old": true, "grand": true, "high": true, "spring": true,
+// cacheNamespace is the version of the dictionaries the address was normalized
+// with and, for a tenant, its name and the version of its words, so results are
+// not served from the cache after a reload changed the dictionaries and each
+// tenant gets its own.
+func cacheNamespace(dictionaries *DictionarySet, tenant *Tenant) string {
+	namespace := dictionaries.Version
+	if tenant != nil {
+		namespace += ":" + tenant.Name + "-" + tenant.version
 	}
-	return commonWords[strings.ToLower(word)]
+	return namespace
 }
 
-func (s *ValidatorService) generateCacheKey(address string) string {
+func (s *ValidatorService) generateCacheKey(address string, namespace string) string {
 	hash := md5.Sum([]byte(strings.ToLower(address)))
-	return "addr:" + hex.EncodeToString(hash[:])
+	return "addr:" + namespace + ":" + hex.EncodeToString(hash[:])
+}
+
+func (s *ValidatorService) generateStructuredCacheKey(address models.StructuredAddress, namespace string) string {
+	fields := strings.Join([]string{
+		address.Line1, address.Line2, address.City, address.State, address.PostalCode, address.Country,
+	}, "|")
+	hash := md5.Sum([]byte(strings.ToLower(fields)))
+	return "saddr:" + namespace + ":" + hex.EncodeToString(hash[:])
+}
+
+// generateReverseCacheKey rounds to 4 decimals (about 11 m) so GPS jitter
+// around the same spot hits the same entry.
+func (s *ValidatorService) generateReverseCacheKey(latitude, longitude float64) string {
+	return fmt.Sprintf("rev:%.4f,%.4f", latitude, longitude)
+}
+
+func (s *ValidatorService) ValidateAddresses(ctx context.Context, items []models.BatchAddressItem, opts models.ValidationOptions, workers int) []models.BatchValidateResult {
+	results := make([]models.BatchValidateResult, len(items))
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.validateBatchItem(ctx, items[i], opts)
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func (s *ValidatorService) validateBatchItem(ctx context.Context, item models.BatchAddressItem, opts models.ValidationOptions) models.BatchValidateResult {
+	result := models.BatchValidateResult{ID: item.ID}
+
+	if strings.TrimSpace(item.Address) == "" {
+		result.Status = "error"
+		result.Error = "address field is required"
+		return result
+	}
+
+	if err := ctx.Err(); err != nil {
+		result.Status = "error"
+		result.Error = fmt.Sprintf("Failed to validate address: %v", err)
+		return result
+	}
+
+	response, err := s.ValidateAddress(ctx, item.Address, opts)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.ValidateAddressResponse = *response
+	return result
 }
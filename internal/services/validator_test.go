@@ -1,17 +1,24 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
 	"testing"
+
+	"github.com/henrique/address-validator/internal/models"
 )
 
 func TestNormalizeInput(t *testing.T) {
 	cache := NewMockCacheService()
-	geocodingService := NewGeocodingService(
-		"test_key_a", "https://test.api",
-		"test_key_b", "https://test.api",
-		cache,
-	)
-	validatorService := NewValidatorService(geocodingService, cache)
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: "https://test.api"}),
+		NewSmartyProvider(ProviderOptions{APIKey: "test_key_b", BaseURL: "https://test.api"}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
 
 	tests := []struct {
 		name              string
@@ -42,9 +49,9 @@ func TestNormalizeInput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := validatorService.normalizeInput(tt.input)
+			result := validatorService.normalizeInput(tt.input, currentDictionaries(), nil)
 
-			if tt.shouldHaveChanges && len(result.Changes) == 0 {
+			if tt.shouldHaveChanges && len(result.Corrections) == 0 {
 				t.Errorf("Expected changes for input %v, but got none", tt.input)
 			}
 
@@ -59,18 +66,72 @@ func TestNormalizeInput(t *testing.T) {
 	}
 }
 
+func TestNormalizeStructuredInput(t *testing.T) {
+	validatorService := NewValidatorService(NewGeocodingService(nil, NewMockCacheService()), NewMockCacheService(), DefaultGazetteer())
+
+	tests := []struct {
+		name  string
+		input models.StructuredAddress
+		want  models.StructuredAddress
+	}{
+		{
+			name:  "Corrections applied per field",
+			input: models.StructuredAddress{Line1: "123 Main Stret", City: "San Fransisco", State: "California", PostalCode: " 94102 "},
+			want:  models.StructuredAddress{Line1: "123 Main street", City: "san francisco", State: "CA", PostalCode: "94102"},
+		},
+		{
+			name:  "Street abbreviations are not expanded in the city",
+			input: models.StructuredAddress{Line1: "500 Market St", City: "St Cloud", State: "MN"},
+			want:  models.StructuredAddress{Line1: "500 Market street", City: "St Cloud", State: "MN"},
+		},
+		{
+			name:  "City alias of the state",
+			input: models.StructuredAddress{Line1: "500 Market St", City: "St Louis", State: "MO"},
+			want:  models.StructuredAddress{Line1: "500 Market street", City: "st. louis", State: "MO"},
+		},
+		{
+			name:  "ZIP+4 written without hyphen",
+			input: models.StructuredAddress{Line1: "1 Main St", City: "Boise", State: "ID", PostalCode: "837010045"},
+			want:  models.StructuredAddress{Line1: "1 Main street", City: "Boise", State: "ID", PostalCode: "83701-0045"},
+		},
+		{
+			name:  "City typo scoped by the ZIP code",
+			input: models.StructuredAddress{Line1: "1 Main St", City: "Bakersfeld", PostalCode: "93301"},
+			want:  models.StructuredAddress{Line1: "1 Main street", City: "bakersfield", PostalCode: "93301"},
+		},
+		{
+			name:  "City names are not corrected in the street lines",
+			input: models.StructuredAddress{Line1: "10 Fransisco Street", City: "Austin", State: "tx"},
+			want:  models.StructuredAddress{Line1: "10 Fransisco Street", City: "Austin", State: "TX"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := validatorService.normalizeStructuredInput(tt.input, currentDictionaries(), nil)
+			if got != tt.want {
+				t.Errorf("normalizeStructuredInput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCacheKeyGeneration(t *testing.T) {
 	cache := NewMockCacheService()
-	geocodingService := NewGeocodingService("test_key_a", "https://test.api", "test_key_b", "https://test.api", cache)
-	validatorService := NewValidatorService(geocodingService, cache)
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: "https://test.api"}),
+		NewSmartyProvider(ProviderOptions{APIKey: "test_key_b", BaseURL: "https://test.api"}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
 
 	addr1 := "123 Main Street"
 	addr2 := "123 Main Street"
 	addr3 := "456 Oak Avenue"
 
-	key1 := validatorService.generateCacheKey(addr1)
-	key2 := validatorService.generateCacheKey(addr2)
-	key3 := validatorService.generateCacheKey(addr3)
+	version := currentDictionaries().Version
+	key1 := validatorService.generateCacheKey(addr1, version)
+	key2 := validatorService.generateCacheKey(addr2, version)
+	key3 := validatorService.generateCacheKey(addr3, version)
 
 	if key1 != key2 {
 		t.Errorf("Same address generated different cache keys: %v != %v", key1, key2)
@@ -79,4 +140,229 @@ func TestCacheKeyGeneration(t *testing.T) {
 	if key1 == key3 {
 		t.Errorf("Different addresses generated same cache key")
 	}
+
+	if key1 == validatorService.generateCacheKey(addr1, "other") {
+		t.Errorf("Different dictionary versions generated same cache key")
+	}
+}
+
+func TestValidateAddressServedFromCache(t *testing.T) {
+	var calls int32
+	server := newGeoapifyTestServer(t, &calls)
+
+	cache := NewMockCacheService()
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
+
+	first, err := validatorService.ValidateAddress(context.Background(), "123 Main Stret, San Francisco, CA", models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	second, err := validatorService.ValidateAddress(context.Background(), "123 Main Stret, San Francisco, CA", models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected 1 provider call, got %d", calls)
+	}
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Cached response differs from original: %+v != %+v", second, first)
+	}
+}
+
+func TestValidateAddresses(t *testing.T) {
+	var calls int32
+	server := newGeoapifyTestServer(t, &calls)
+
+	cache := NewMockCacheService()
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
+
+	items := []models.BatchAddressItem{
+		{ID: "a", Address: "123 Main Street, San Francisco, CA"},
+		{ID: "b", Address: "   "},
+		{ID: "c", Address: "456 Oak Ave, San Francisco, CA"},
+		{ID: "d", Address: "123 Main Street, San Francisco, CA"},
+	}
+
+	results := validatorService.ValidateAddresses(context.Background(), items, models.ValidationOptions{}, 2)
+
+	if len(results) != len(items) {
+		t.Fatalf("Expected %d results, got %d", len(items), len(results))
+	}
+
+	wantStatus := []string{"success", "error", "success", "success"}
+	for i, result := range results {
+		if result.ID != items[i].ID {
+			t.Errorf("Result %d has ID %v, want %v", i, result.ID, items[i].ID)
+		}
+		if result.Status != wantStatus[i] {
+			t.Errorf("Result %s status = %v, want %v (error: %v)", result.ID, result.Status, wantStatus[i], result.Error)
+		}
+	}
+
+	if results[1].Error == "" {
+		t.Error("Expected per-item error for empty address")
+	}
+}
+
+func TestValidateAddressMinConfidence(t *testing.T) {
+	var calls int32
+	server := newGeoapifyTestServer(t, &calls)
+
+	cache := NewMockCacheService()
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
+
+	accepted, err := validatorService.ValidateAddress(context.Background(), "123 Main Stret, San Francisco, CA", models.ValidationOptions{MinConfidence: 0.9})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	if accepted.Status != "success" || accepted.Confidence != 0.95 || accepted.Verdict != VerdictVerified {
+		t.Errorf("Unexpected response: status=%v confidence=%v verdict=%v", accepted.Status, accepted.Confidence, accepted.Verdict)
+	}
+
+	rejected, err := validatorService.ValidateAddress(context.Background(), "123 Main Stret, San Francisco, CA", models.ValidationOptions{MinConfidence: 0.99})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	if rejected.Status != "error" || rejected.Data != nil || rejected.Confidence != 0.95 {
+		t.Errorf("Expected rejection below min_confidence, got status=%v confidence=%v", rejected.Status, rejected.Confidence)
+	}
+}
+
+func TestValidateAddressZeroConfidence(t *testing.T) {
+	fake := &fakeProvider{
+		name:         "fake",
+		capabilities: CapabilityGeocode,
+		candidates: []models.Candidate{
+			{Address: &models.AddressData{City: "San Francisco", Precision: PrecisionRooftop}, Provider: "fake", Score: 0},
+		},
+	}
+	cache := NewMockCacheService()
+	validatorService := NewValidatorService(NewGeocodingService([]Provider{fake}, cache), cache, DefaultGazetteer())
+
+	response, err := validatorService.ValidateAddress(context.Background(), "123 Main St, San Francisco, CA", models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if confidence, exists := fields["confidence"]; !exists || confidence != 0.0 {
+		t.Errorf("confidence = %v (present: %v), want 0", confidence, exists)
+	}
+}
+
+func TestValidateAddressCandidates(t *testing.T) {
+	var calls int32
+	server := newTestServer(t, &calls, geoapifyAmbiguousTestResponse)
+
+	cache := NewMockCacheService()
+	geocodingService := NewGeocodingService([]Provider{
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL, MaxResults: 5}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
+
+	result, err := validatorService.ValidateAddress(context.Background(), "100 Main Street, Springfield", models.ValidationOptions{MaxCandidates: 2})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	if len(result.Candidates) != 2 {
+		t.Fatalf("Expected 2 candidates, got %d", len(result.Candidates))
+	}
+
+	if result.Candidates[0].Address.State != "MA" || result.Candidates[1].Address.State != "IL" {
+		t.Errorf("Candidates not ranked by score: %v, %v", result.Candidates[0].Address.State, result.Candidates[1].Address.State)
+	}
+
+	if result.Candidates[0].Score < result.Candidates[1].Score {
+		t.Errorf("Candidate scores not descending: %v < %v", result.Candidates[0].Score, result.Candidates[1].Score)
+	}
+
+	if result.Data.State != "MA" {
+		t.Errorf("Data should be the best candidate, got state %v", result.Data.State)
+	}
+
+	if result.Verdict != VerdictAmbiguous {
+		t.Errorf("Verdict = %v, want %v", result.Verdict, VerdictAmbiguous)
+	}
+
+	withoutCandidates, err := validatorService.ValidateAddress(context.Background(), "100 Main Street, Springfield", models.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAddress returned error: %v", err)
+	}
+
+	if withoutCandidates.Candidates != nil {
+		t.Errorf("Expected no candidates when max_candidates is not set, got %d", len(withoutCandidates.Candidates))
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected 1 provider call, got %d", calls)
+	}
+}
+
+func TestReverseGeocode(t *testing.T) {
+	var calls int32
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(geoapifyTestResponse))
+	}))
+	t.Cleanup(server.Close)
+
+	cache := NewMockCacheService()
+	geocodingService := NewGeocodingService([]Provider{
+		newFakeProvider("forward-only", "Austin"),
+		NewGeoapifyProvider(ProviderOptions{APIKey: "test_key_a", BaseURL: server.URL + "/v1/geocode/search"}),
+	}, cache)
+	validatorService := NewValidatorService(geocodingService, cache, DefaultGazetteer())
+
+	first, err := validatorService.ReverseGeocode(context.Background(), 37.77491, -122.41942)
+	if err != nil {
+		t.Fatalf("ReverseGeocode returned error: %v", err)
+	}
+
+	if first.Status != "success" || first.Provider != "geoapify" {
+		t.Fatalf("Unexpected response: %+v", first)
+	}
+	if first.Data.City != "San Francisco" {
+		t.Errorf("City = %v, want San Francisco", first.Data.City)
+	}
+	if path != "/v1/geocode/reverse" {
+		t.Errorf("Request path = %v, want /v1/geocode/reverse", path)
+	}
+
+	second, err := validatorService.ReverseGeocode(context.Background(), 37.77489, -122.41938)
+	if err != nil {
+		t.Fatalf("ReverseGeocode returned error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected nearby coordinates to be served from cache, got %d provider calls", calls)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Cached response differs from original: %+v != %+v", second, first)
+	}
 }